@@ -0,0 +1,293 @@
+// Command indexer loads the catalog export into Meilisearch: it applies the
+// index settings and synonyms, enriches each product with extracted
+// attributes and uploads the documents in batches.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"meilisearch/internal/config"
+	"meilisearch/internal/metrics"
+	"meilisearch/internal/telemetry"
+	"meilisearch/service"
+
+	"github.com/meilisearch/meilisearch-go"
+	"go.opentelemetry.io/otel/codes"
+)
+
+// cleanData converts "NULL" strings to nil and ensures proper data types
+func cleanData(data []map[string]interface{}) []map[string]interface{} {
+	for _, item := range data {
+		for key, value := range item {
+			// Convert "NULL" strings to actual null
+			if str, ok := value.(string); ok && strings.ToUpper(str) == "NULL" {
+				item[key] = nil
+			}
+			// Ensure numeric fields are properly typed
+			if key == "id" || key == "category_id" || key == "created_by" || key == "updated_by" || key == "is_active" {
+				if str, ok := value.(string); ok {
+					if str == "NULL" {
+						item[key] = nil
+					}
+				}
+			}
+		}
+	}
+	return data
+}
+
+func main() {
+	if err := run(); err != nil {
+		log.Fatalf("Indexing failed: %v", err)
+	}
+}
+
+// run indexes the catalog inside one trace. Errors are returned rather than
+// fatal, so the root span ends and the traces are flushed on failed runs too.
+func run() (err error) {
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("invalid configuration: %w", err)
+	}
+
+	shutdownTracing, err := telemetry.Setup(context.Background(), cfg.TracingOptions("catalog-indexer"))
+	if err != nil {
+		return fmt.Errorf("failed to set up tracing: %w", err)
+	}
+	defer func() {
+		if err := shutdownTracing(context.Background()); err != nil {
+			fmt.Printf("⚠️  Could not flush traces: %v\n", err)
+		}
+	}()
+
+	// The whole run is one trace, with a span per Meilisearch call
+	ctx, span := telemetry.Tracer().Start(context.Background(), "indexer.run")
+	defer func() {
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+		span.End()
+	}()
+
+	if err := indexCatalog(ctx, cfg); err != nil {
+		return err
+	}
+
+	if cfg.PushgatewayURL != "" {
+		if err := metrics.PushIndexerMetrics(cfg.PushgatewayURL); err != nil {
+			fmt.Printf("⚠️  Could not push metrics to %s: %v\n", cfg.PushgatewayURL, err)
+		} else {
+			fmt.Printf("📤 Metrics pushed to %s\n", cfg.PushgatewayURL)
+		}
+	}
+	return nil
+}
+
+// indexCatalog applies the index settings and synonyms, uploads the enriched
+// catalog and runs a few test searches
+func indexCatalog(ctx context.Context, cfg *config.Config) error {
+	// Initialize Meilisearch client for v0.32.0
+	client := telemetry.InstrumentClient(cfg.NewClient())
+
+	// Test connection to Meilisearch
+	if _, err := client.HealthWithContext(ctx); err != nil {
+		return fmt.Errorf("failed to connect to Meilisearch: %w", err)
+	}
+	fmt.Println("✅ Connected to Meilisearch successfully")
+
+	// Create or get the index
+	indexName := service.ProductIndexUID
+	index := client.Index(indexName)
+
+	// Apply index settings so search filters (e.g. is_active) are available
+	settingsTask, err := service.ApplyIndexSettings(ctx, index)
+	if err != nil {
+		return fmt.Errorf("failed to update index settings: %w", err)
+	}
+	fmt.Printf("⚙️  Index settings update enqueued (task %d)\n", settingsTask.TaskUID)
+
+	// Products soft-deleted through the API stay inactive: the upload replaces
+	// whole documents, so their IDs are read first, once soft_deleted is
+	// filterable
+	if _, err := client.WaitForTaskWithContext(ctx, settingsTask.TaskUID, 100*time.Millisecond); err != nil {
+		return fmt.Errorf("failed to wait for index settings: %w", err)
+	}
+	softDeleted, err := service.SoftDeletedIDs(ctx, index)
+	if err != nil {
+		return fmt.Errorf("failed to read soft-deleted products: %w", err)
+	}
+
+	// Push the synonym dictionary to the index settings
+	synonyms, err := service.LoadSynonyms(service.DefaultSynonymsFile)
+	if err != nil {
+		return fmt.Errorf("failed to load synonyms: %w", err)
+	}
+	synonymsTask, err := service.ApplySynonyms(ctx, index, synonyms)
+	if err != nil {
+		return fmt.Errorf("failed to update synonyms: %w", err)
+	}
+	fmt.Printf("📚 Synonyms v%d (%d groups) update enqueued (task %d)\n", synonyms.Version, len(synonyms.Groups), synonymsTask.TaskUID)
+
+	// Read and parse JSON file
+	jsonFile, err := os.Open(cfg.DataFile)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", cfg.DataFile, err)
+	}
+	defer jsonFile.Close()
+
+	byteValue, err := io.ReadAll(jsonFile)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", cfg.DataFile, err)
+	}
+
+	var sku []map[string]interface{}
+	if err := json.Unmarshal(byteValue, &sku); err != nil {
+		return fmt.Errorf("failed to parse JSON: %w", err)
+	}
+
+	fmt.Printf("📊 Loaded %d documents from %s\n", len(sku), cfg.DataFile)
+
+	// Clean the data before sending to Meilisearch
+	sku = cleanData(sku)
+
+	// Extract brands, categories and canonical attributes (thickness, length, load, ...) from product names
+	brandDictionary, err := service.LoadBrandDictionary(service.DefaultBrandsFile)
+	if err != nil {
+		return fmt.Errorf("failed to load brand dictionary: %w", err)
+	}
+	categoryTree, err := service.LoadCategoryTree(service.DefaultCategoriesFile)
+	if err != nil {
+		return fmt.Errorf("failed to load category hierarchy: %w", err)
+	}
+	brands := service.NewBrandMatcher(brandDictionary)
+	categories := service.NewCategoryMatcher(categoryTree)
+	for _, doc := range sku {
+		service.EnrichDocument(doc, brands, categories)
+	}
+	if kept := service.KeepSoftDeletes(sku, softDeleted); kept > 0 {
+		fmt.Printf("🗑️  Keeping %d soft-deleted products inactive\n", kept)
+	}
+
+	// Upload documents in batches
+	batchSize := cfg.BatchSize
+	totalBatches := (len(sku) + batchSize - 1) / batchSize
+
+	fmt.Printf("🚀 Starting upload of %d documents in %d batches...\n", len(sku), totalBatches)
+
+	var lastTaskUID int64
+
+	for i := 0; i < len(sku); i += batchSize {
+		end := i + batchSize
+		if end > len(sku) {
+			end = len(sku)
+		}
+
+		batchNum := (i / batchSize) + 1
+		fmt.Printf("📦 Uploading batch %d/%d (%d documents)...\n", batchNum, totalBatches, end-i)
+
+		batchStart := time.Now()
+		task, err := index.AddDocumentsWithContext(ctx, sku[i:end], "id")
+		if err != nil {
+			return fmt.Errorf("failed to upload batch %d-%d: %w", i, end, err)
+		}
+		metrics.IndexerBatchDuration.Observe(time.Since(batchStart).Seconds())
+		metrics.IndexerDocuments.Add(float64(end - i))
+		lastTaskUID = task.TaskUID
+
+		fmt.Printf("✅ Batch %d/%d uploaded successfully\n", batchNum, totalBatches)
+
+		// Small delay between batches to avoid overwhelming the server
+		if i+batchSize < len(sku) {
+			time.Sleep(100 * time.Millisecond)
+		}
+	}
+
+	fmt.Println("🎉 All documents uploaded successfully!")
+
+	// Wait for the last task to complete before getting stats
+	if lastTaskUID != 0 {
+		fmt.Printf("⏳ Waiting for indexing task %d to complete...\n", lastTaskUID)
+		for {
+			taskStatus, err := client.GetTaskWithContext(ctx, lastTaskUID)
+			if err != nil {
+				fmt.Printf("⚠️  Could not get task status: %v\n", err)
+				break
+			}
+			if taskStatus.Status == "succeeded" {
+				fmt.Println("✅ Indexing complete!")
+				break
+			} else if taskStatus.Status == "failed" {
+				fmt.Printf("❌ Indexing failed: %v\n", taskStatus.Error)
+				break
+			}
+			time.Sleep(500 * time.Millisecond)
+		}
+	}
+
+	// Get index stats
+	stats, err := index.GetStatsWithContext(ctx)
+	if err != nil {
+		fmt.Printf("⚠️  Could not get index stats: %v\n", err)
+	} else {
+		fmt.Printf("📈 Index stats: %d documents indexed\n", stats.NumberOfDocuments)
+	}
+
+	// Test search functionality
+	fmt.Println("\n🔍 Testing search functionality...")
+
+	// Test 1: Search for "FEVICOL"
+	fmt.Println("Test 1: Searching for 'FEVICOL'...")
+	searchRes, err := index.SearchWithContext(ctx, "FEVICOL", &meilisearch.SearchRequest{
+		Limit: 5,
+	})
+	if err != nil {
+		fmt.Printf("❌ Search failed: %v\n", err)
+	} else {
+		fmt.Printf("✅ Found %d results for 'FEVICOL'\n", len(searchRes.Hits))
+		if len(searchRes.Hits) > 0 {
+			fmt.Printf("   First result: %s\n", searchRes.Hits[0].(map[string]interface{})["name"])
+		}
+	}
+
+	// Test 2: Search for "Adhesives" category
+	fmt.Println("\nTest 2: Searching for 'Adhesives' category...")
+	searchRes2, err := index.SearchWithContext(ctx, "Adhesives", &meilisearch.SearchRequest{
+		Limit: 3,
+	})
+	if err != nil {
+		fmt.Printf("❌ Search failed: %v\n", err)
+	} else {
+		fmt.Printf("✅ Found %d results for 'Adhesives'\n", len(searchRes2.Hits))
+		if len(searchRes2.Hits) > 0 {
+			fmt.Printf("   First result: %s\n", searchRes2.Hits[0].(map[string]interface{})["name"])
+		}
+	}
+
+	// Test 3: Search by SKU code
+	fmt.Println("\nTest 3: Searching for SKU 'ADH1'...")
+	searchRes3, err := index.SearchWithContext(ctx, "ADH1", &meilisearch.SearchRequest{
+		Limit: 1,
+	})
+	if err != nil {
+		fmt.Printf("❌ Search failed: %v\n", err)
+	} else {
+		fmt.Printf("✅ Found %d results for 'ADH1'\n", len(searchRes3.Hits))
+		if len(searchRes3.Hits) > 0 {
+			result := searchRes3.Hits[0].(map[string]interface{})
+			fmt.Printf("   SKU: %s, Name: %s, Category: %s\n",
+				result["sku"], result["name"], result["category_name"])
+		}
+	}
+
+	fmt.Println("\n🎉 All tests completed!")
+
+	return nil
+}
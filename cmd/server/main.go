@@ -0,0 +1,145 @@
+// Command server runs the product catalog HTTP API
+package main
+
+import (
+	"context"
+	"errors"
+	"log"
+	"log/slog"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"meilisearch/handler"
+	"meilisearch/internal/auth"
+	"meilisearch/internal/config"
+	"meilisearch/internal/metrics"
+	"meilisearch/internal/ratelimit"
+	"meilisearch/internal/telemetry"
+	"meilisearch/service"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// serviceName identifies the API server in traces
+const serviceName = "catalog-api"
+
+// traceFlushTimeout bounds exporting the last spans at shutdown
+const traceFlushTimeout = 5 * time.Second
+
+func main() {
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalf("Invalid configuration: %v", err)
+	}
+
+	logger := cfg.NewLogger()
+	slog.SetDefault(logger)
+
+	shutdownTracing, err := telemetry.Setup(context.Background(), cfg.TracingOptions(serviceName))
+	if err != nil {
+		fatal("failed to set up tracing", err)
+	}
+
+	// Initialize Meilisearch client; calls are traced and timed for /metrics
+	client := telemetry.InstrumentClient(cfg.NewClient())
+	prometheus.MustRegister(metrics.NewIndexCollector(client, service.ProductIndexUID))
+
+	// Start in degraded mode when Meilisearch is down: the readiness probe
+	// fails until it comes back, while the liveness probe keeps passing
+	healthCtx, cancelHealth := context.WithTimeout(context.Background(), cfg.HealthTimeout)
+	_, err = client.HealthWithContext(healthCtx)
+	cancelHealth()
+	if err != nil {
+		slog.Warn("Meilisearch is unavailable, starting in degraded mode",
+			"url", cfg.MeilisearchURL, "error", err)
+	} else {
+		slog.Info("connected to Meilisearch", "url", cfg.MeilisearchURL)
+	}
+
+	authenticator := auth.NewAuthenticator(cfg.AuthOptions()...)
+	if authenticator.Disabled() {
+		slog.Warn("authentication is disabled: every caller has the admin role")
+	} else if !authenticator.Enabled() {
+		slog.Warn("no credentials configured: only read routes are available, set API_KEYS or JWT_SECRET")
+	}
+
+	limiter := ratelimit.NewLimiter(cfg.RateLimitOptions()...)
+
+	// Setup routes
+	readiness := handler.NewReadiness()
+	healthService := service.NewHealthService(client, cfg.HealthOptions()...)
+	tokenService := service.NewSearchTokenService(client, cfg.SearchKeyUID, cfg.SearchTokenOptions()...)
+	mux := handler.SetupRoutes(client, readiness, healthService, tokenService, authenticator, limiter, cfg.ProductOptions()...)
+
+	// Apply middleware; the request ID is assigned first so every span, log
+	// line and error body carries it
+	root := handler.RequestIDMiddleware(handler.TracingMiddleware(
+		handler.LoggingMiddleware(handler.MetricsMiddleware(handler.CORSMiddleware(cfg.CORS, mux))),
+	))
+
+	server := &http.Server{
+		Addr:              ":" + cfg.Port,
+		Handler:           root,
+		ReadTimeout:       cfg.ReadTimeout,
+		ReadHeaderTimeout: cfg.ReadHeaderTimeout,
+		WriteTimeout:      cfg.WriteTimeout,
+		IdleTimeout:       cfg.IdleTimeout,
+		ErrorLog:          slog.NewLogLogger(logger.Handler(), slog.LevelError),
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	// Bind the port before reporting ready, so probes never see a ready
+	// server that cannot accept connections
+	listener, err := net.Listen("tcp", server.Addr)
+	if err != nil {
+		fatal("API server failed", err)
+	}
+	slog.Info("starting API server", "port", cfg.Port)
+	serverErr := make(chan error, 1)
+	go func() {
+		serverErr <- server.Serve(listener)
+	}()
+	readiness.SetReady(true)
+
+	select {
+	case err := <-serverErr:
+		fatal("API server failed", err)
+	case <-ctx.Done():
+	}
+	// A second signal kills the process instead of waiting for the drain
+	stop()
+
+	// Report not-ready first so load balancers stop sending traffic, then
+	// stop accepting connections and let in-flight requests finish
+	readiness.SetReady(false)
+	slog.Info("shutting down, reporting not ready", "drain_delay", cfg.ShutdownDelay)
+	time.Sleep(cfg.ShutdownDelay)
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
+	defer cancel()
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		fatal("graceful shutdown failed", err)
+	}
+	if err := <-serverErr; err != nil && !errors.Is(err, http.ErrServerClosed) {
+		fatal("API server failed", err)
+	}
+	// The drain may have used up the shutdown timeout, so tracing gets its own
+	flushCtx, cancelFlush := context.WithTimeout(context.Background(), traceFlushTimeout)
+	defer cancelFlush()
+	if err := shutdownTracing(flushCtx); err != nil {
+		slog.Error("failed to flush traces", "error", err)
+	}
+	slog.Info("API server stopped")
+}
+
+// fatal logs an error and exits
+func fatal(message string, err error) {
+	slog.Error(message, "error", err)
+	os.Exit(1)
+}
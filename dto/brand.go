@@ -0,0 +1,27 @@
+package dto
+
+// Brand represents a brand and the aliases it is recognized by in product names
+type Brand struct {
+	Name    string   `json:"name"`
+	Aliases []string `json:"aliases"`
+}
+
+// BrandDictionary represents the versioned brand dictionary
+type BrandDictionary struct {
+	Version int     `json:"version"`
+	Brands  []Brand `json:"brands"`
+}
+
+// CategoryCount represents the number of products in a category
+type CategoryCount struct {
+	Category string `json:"category"`
+	Count    int64  `json:"count"`
+}
+
+// BrandSummary represents a brand with its product counts per category
+type BrandSummary struct {
+	Name         string          `json:"name"`
+	Aliases      []string        `json:"aliases,omitempty"`
+	ProductCount int64           `json:"product_count"`
+	Categories   []CategoryCount `json:"categories"`
+}
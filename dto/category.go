@@ -0,0 +1,26 @@
+package dto
+
+// CategoryNode is one node of the configured category hierarchy. Nodes listing
+// catalog categories take those products; their children split them further
+// by keywords found in product names, the first matching child winning.
+type CategoryNode struct {
+	Name       string         `json:"name"`
+	Categories []string       `json:"categories,omitempty"`
+	Keywords   []string       `json:"keywords,omitempty"`
+	Children   []CategoryNode `json:"children,omitempty"`
+}
+
+// CategoryTree represents the versioned category hierarchy
+type CategoryTree struct {
+	Version    int            `json:"version"`
+	Categories []CategoryNode `json:"categories"`
+}
+
+// CategorySummary is a category hierarchy node with its active product count
+type CategorySummary struct {
+	Name     string            `json:"name"`
+	Path     string            `json:"path"`
+	Level    int               `json:"level"`
+	Count    int64             `json:"count"`
+	Children []CategorySummary `json:"children,omitempty"`
+}
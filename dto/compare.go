@@ -0,0 +1,31 @@
+package dto
+
+// ComparedProduct identifies one product in a comparison
+type ComparedProduct struct {
+	ID           int     `json:"id"`
+	SKU          string  `json:"sku"`
+	Name         string  `json:"name"`
+	CategoryName string  `json:"category_name"`
+	UnitType     *string `json:"unit_type"`
+}
+
+// CompareAttribute is one row of a comparison matrix. Values holds one entry
+// per compared product, in product order, and is nil where a product lacks
+// the attribute.
+type CompareAttribute struct {
+	Field   string        `json:"field"`
+	Label   string        `json:"label"`
+	Unit    string        `json:"unit,omitempty"`
+	Values  []interface{} `json:"values"`
+	Differs bool          `json:"differs"`
+}
+
+// CompareResponse is a side-by-side attribute matrix of products from one
+// category
+type CompareResponse struct {
+	CategoryName string             `json:"category_name"`
+	Products     []ComparedProduct  `json:"products"`
+	Attributes   []CompareAttribute `json:"attributes"`
+	// Differing lists the fields of the rows whose values differ
+	Differing []string `json:"differing"`
+}
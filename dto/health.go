@@ -0,0 +1,25 @@
+package dto
+
+import "time"
+
+// Outcomes of a single readiness check
+const (
+	CheckPass = "pass"
+	CheckFail = "fail"
+)
+
+// HealthCheckResult is the outcome of one readiness check
+type HealthCheckResult struct {
+	Name    string `json:"name"`
+	Status  string `json:"status"`
+	Message string `json:"message"`
+}
+
+// ReadinessReport summarizes whether the service can serve search traffic
+type ReadinessReport struct {
+	Ready     bool                `json:"ready"`
+	Checks    []HealthCheckResult `json:"checks"`
+	CheckedAt time.Time           `json:"checked_at"`
+	// Cached is set when the report was served from the readiness cache
+	Cached bool `json:"cached"`
+}
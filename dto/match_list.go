@@ -0,0 +1,51 @@
+package dto
+
+// MatchListRequest represents a pasted material list to match against the
+// catalog, given as text with one item per line or as separate lines
+type MatchListRequest struct {
+	Text  string   `json:"text,omitempty"`
+	Lines []string `json:"lines,omitempty"`
+	// Category restricts matches to one catalog category
+	Category string `json:"category,omitempty"`
+	// Alternatives is the number of runner-up candidates listed per line
+	Alternatives int `json:"alternatives,omitempty"`
+}
+
+// MatchCandidate represents a product matched to a material list line
+type MatchCandidate struct {
+	ID           int     `json:"id"`
+	SKU          string  `json:"sku"`
+	Name         string  `json:"name"`
+	CategoryName string  `json:"category_name"`
+	Brand        string  `json:"brand,omitempty"`
+	SellingPrice *string `json:"selling_price"`
+	// Score is the search relevance from 0 to 1
+	Score float64 `json:"score"`
+}
+
+// MatchListLine represents the match for one line of a material list
+type MatchListLine struct {
+	// Line is the 1-based line number in the submitted list
+	Line  int    `json:"line"`
+	Input string `json:"input"`
+	// Query is the line with its quantity removed, as searched
+	Query         string `json:"query"`
+	Quantity      int    `json:"quantity"`
+	QuantityFound bool   `json:"quantity_found"`
+
+	Match        *MatchCandidate  `json:"match"`
+	Alternatives []MatchCandidate `json:"alternatives"`
+	Confidence   float64          `json:"confidence"`
+	ParsedQuery  *ParsedQuery     `json:"parsed_query,omitempty"`
+
+	NeedsReview   bool     `json:"needs_review"`
+	ReviewReasons []string `json:"review_reasons"`
+	Error         string   `json:"error,omitempty"`
+}
+
+// MatchListResponse represents the matches for a material list
+type MatchListResponse struct {
+	Lines       []MatchListLine `json:"lines"`
+	Matched     int             `json:"matched"`
+	NeedsReview int             `json:"needs_review"`
+}
@@ -1,6 +1,45 @@
 package dto
 
-import "time"
+import (
+	"encoding/json"
+	"strings"
+	"time"
+)
+
+// TimestampLayout is the layout used for timestamps in the catalog export
+const TimestampLayout = "2006-01-02 15:04:05"
+
+// Timestamp is a time.Time that (un)marshals using TimestampLayout
+type Timestamp struct {
+	time.Time
+}
+
+// UnmarshalJSON parses a catalog timestamp, accepting RFC 3339 as a fallback
+func (t *Timestamp) UnmarshalJSON(data []byte) error {
+	raw := strings.Trim(string(data), `"`)
+	if raw == "" || raw == "null" || strings.EqualFold(raw, "NULL") {
+		t.Time = time.Time{}
+		return nil
+	}
+
+	parsed, err := time.Parse(TimestampLayout, raw)
+	if err != nil {
+		parsed, err = time.Parse(time.RFC3339, raw)
+		if err != nil {
+			return err
+		}
+	}
+	t.Time = parsed
+	return nil
+}
+
+// MarshalJSON formats the timestamp using TimestampLayout
+func (t Timestamp) MarshalJSON() ([]byte, error) {
+	if t.IsZero() {
+		return []byte("null"), nil
+	}
+	return []byte(`"` + t.Format(TimestampLayout) + `"`), nil
+}
 
 // Product represents a product in the catalog
 type Product struct {
@@ -14,8 +53,8 @@ type Product struct {
 	Status               string    `json:"status"`
 	CreatedBy            int       `json:"created_by"`
 	UpdatedBy            int       `json:"updated_by"`
-	CreatedAt            time.Time `json:"created_at"`
-	UpdatedAt            time.Time `json:"updated_at"`
+	CreatedAt            Timestamp `json:"created_at"`
+	UpdatedAt            Timestamp `json:"updated_at"`
 	PerUnitMRPPrice      *string   `json:"per_unit_mrp_price"`
 	UnitType             *string   `json:"unit_type"`
 	PerUnitSellingPrice  *string   `json:"per_unit_selling_price"`
@@ -25,27 +64,119 @@ type Product struct {
 	IsActive             int       `json:"is_active"`
 	Discount             *string   `json:"discount"`
 	CategoryName         string    `json:"category_name"`
+
+	// Attributes extracted from the product name at index time
+	Brand       string   `json:"brand,omitempty"`
+	ThicknessMM *float64 `json:"thickness_mm,omitempty"`
+	LengthMM    *float64 `json:"length_mm,omitempty"`
+	HeightMM    *float64 `json:"height_mm,omitempty"`
+	LengthIN    *float64 `json:"length_in,omitempty"`
+	HeightIN    *float64 `json:"height_in,omitempty"`
+	LoadKG      *float64 `json:"load_kg,omitempty"`
+	WeightG     *float64 `json:"weight_g,omitempty"`
+	VolumeML    *float64 `json:"volume_ml,omitempty"`
+	SheetSize   *string  `json:"sheet_size,omitempty"`
+	Crank       *int     `json:"crank,omitempty"`
+	CloseType   *string  `json:"close_type,omitempty"`
+	Finish      *string  `json:"finish,omitempty"`
+
+	// VariantGroup is shared by products differing only in size, load or pack
+	VariantGroup string `json:"variant_group,omitempty"`
+	// Category holds the hierarchy levels, e.g. {"lvl0": "Hardware", "lvl1": "Hardware > Channels"}
+	Category map[string]string `json:"category,omitempty"`
+	// Price is the selling price as a number, for sorting
+	Price *float64 `json:"price,omitempty"`
 }
 
 // ProductSearchRequest represents a search request for products
 type ProductSearchRequest struct {
-	Query     string `json:"q"`
-	Limit     int    `json:"limit"`
-	Offset    int    `json:"offset"`
-	Category  string `json:"category,omitempty"`
-	Status    string `json:"status,omitempty"`
-	SortBy    string `json:"sort_by,omitempty"`
-	SortOrder string `json:"sort_order,omitempty"`
+	Query           string `json:"q"`
+	Limit           int    `json:"limit"`
+	Offset          int    `json:"offset"`
+	Category        string `json:"category,omitempty"`
+	CategoryPath    string `json:"category_path,omitempty"`
+	Status          string `json:"status,omitempty"`
+	Brand           string `json:"brand,omitempty"`
+	SortBy          string `json:"sort_by,omitempty"`
+	SortOrder       string `json:"sort_order,omitempty"`
+	IncludeInactive bool   `json:"include_inactive,omitempty"`
+
+	// Facets lists filterable attributes to return value counts for
+	Facets []string `json:"facets,omitempty"`
+
+	// RawQuery disables unit-aware query parsing
+	RawQuery bool `json:"raw_query,omitempty"`
+
+	// CollapseVariants returns one product per variant group
+	CollapseVariants bool `json:"collapse_variants,omitempty"`
+
+	// ShowRankingScore returns each hit's relevance score from 0 to 1
+	ShowRankingScore bool `json:"show_ranking_score,omitempty"`
+
+	// Page-based pagination; when Page is set Limit and Offset are ignored
+	Page    int `json:"page,omitempty"`
+	PerPage int `json:"per_page,omitempty"`
+
+	// Highlighting and cropping of matched attributes
+	Highlight        []string `json:"highlight,omitempty"`
+	Crop             []string `json:"crop,omitempty"`
+	CropLength       int      `json:"crop_length,omitempty"`
+	CropMarker       string   `json:"crop_marker,omitempty"`
+	HighlightPreTag  string   `json:"highlight_pre_tag,omitempty"`
+	HighlightPostTag string   `json:"highlight_post_tag,omitempty"`
+}
+
+// MatchPosition locates a query match within an attribute value
+type MatchPosition struct {
+	Start  int `json:"start"`
+	Length int `json:"length"`
+}
+
+// ProductHit represents a product returned by search, with optional
+// highlighted/cropped values and match positions
+type ProductHit struct {
+	Product
+	Formatted       map[string]interface{}     `json:"_formatted,omitempty"`
+	MatchesPosition map[string][]MatchPosition `json:"matches_position,omitempty"`
+	// VariantCount is the size of the hit's variant group in collapsed searches
+	VariantCount int `json:"variant_count,omitempty"`
+	// RankingScore is the hit's relevance from 0 to 1 when requested
+	RankingScore float64 `json:"ranking_score,omitempty"`
 }
 
 // ProductSearchResponse represents a search response for products
 type ProductSearchResponse struct {
-	Hits       []Product `json:"hits"`
-	TotalHits  int       `json:"total_hits"`
-	Processing bool      `json:"processing"`
-	Query      string    `json:"query"`
-	Limit      int       `json:"limit"`
-	Offset     int       `json:"offset"`
+	Hits       []ProductHit `json:"hits"`
+	TotalHits  int          `json:"total_hits"`
+	Processing bool         `json:"processing"`
+	Query      string       `json:"query"`
+	Limit      int          `json:"limit"`
+	Offset     int          `json:"offset"`
+	Page       int          `json:"page,omitempty"`
+	TotalPages int          `json:"total_pages,omitempty"`
+
+	ParsedQuery *ParsedQuery                `json:"parsed_query,omitempty"`
+	Facets      map[string]map[string]int64 `json:"facets,omitempty"`
+}
+
+// QueryMeasurement represents a number with a unit recognized in a query
+type QueryMeasurement struct {
+	Raw   string  `json:"raw"`
+	Unit  string  `json:"unit"`
+	Value float64 `json:"value"`
+	// Canonical is the value converted to the unit of the attribute fields
+	Canonical float64 `json:"canonical"`
+	Filter    string  `json:"filter"`
+}
+
+// ParsedQuery describes how a search query was split into attribute filters
+// and free text
+type ParsedQuery struct {
+	Text         string             `json:"text"`
+	Measurements []QueryMeasurement `json:"measurements"`
+	// Applied is false when the attribute filters matched nothing and the
+	// search fell back to the original query text
+	Applied bool `json:"applied"`
 }
 
 // ProductCreateRequest represents a request to create a new product
@@ -104,3 +235,35 @@ type TaskStatus struct {
 		Link    string `json:"link"`
 	} `json:"error,omitempty"`
 }
+
+// MultiSearchRequest represents a batch of product searches executed in one
+// round trip. It accepts either {"queries": [...]} or a bare JSON array.
+type MultiSearchRequest struct {
+	Queries []ProductSearchRequest `json:"queries"`
+}
+
+// UnmarshalJSON decodes either form of a multi-search request body
+func (m *MultiSearchRequest) UnmarshalJSON(data []byte) error {
+	if trimmed := strings.TrimSpace(string(data)); strings.HasPrefix(trimmed, "[") {
+		return json.Unmarshal(data, &m.Queries)
+	}
+
+	type alias MultiSearchRequest
+	return json.Unmarshal(data, (*alias)(m))
+}
+
+// MultiSearchResult represents the outcome of one query in a multi-search
+type MultiSearchResult struct {
+	Index   int                    `json:"index"`
+	Success bool                   `json:"success"`
+	Result  *ProductSearchResponse `json:"result,omitempty"`
+	Error   string                 `json:"error,omitempty"`
+	Code    string                 `json:"code,omitempty"`
+}
+
+// MultiSearchResponse represents the ordered results of a multi-search
+type MultiSearchResponse struct {
+	Results   []MultiSearchResult `json:"results"`
+	Succeeded int                 `json:"succeeded"`
+	Failed    int                 `json:"failed"`
+}
@@ -0,0 +1,75 @@
+package dto
+
+import "time"
+
+// QuoteItemRequest represents one requested quote line. The product is given
+// by its ID or its SKU.
+type QuoteItemRequest struct {
+	ProductID int    `json:"product_id,omitempty"`
+	SKU       string `json:"sku,omitempty"`
+	Quantity  int    `json:"quantity"`
+}
+
+// QuoteCreateRequest represents a request to build a priced quote
+type QuoteCreateRequest struct {
+	Customer string             `json:"customer,omitempty"`
+	Notes    string             `json:"notes,omitempty"`
+	Items    []QuoteItemRequest `json:"items"`
+}
+
+// QuoteLine represents one priced line of a quote. Amounts are nil when the
+// product has no usable price.
+type QuoteLine struct {
+	Line         int     `json:"line"`
+	ProductID    int     `json:"product_id"`
+	SKU          string  `json:"sku"`
+	Name         string  `json:"name"`
+	CategoryName string  `json:"category_name"`
+	Quantity     int     `json:"quantity"`
+	UnitType     *string `json:"unit_type"`
+
+	// PriceStatus is "priced", "missing_price" or "invalid_price"
+	PriceStatus string `json:"price_status"`
+	// PriceSource names the catalog field the unit price came from
+	PriceSource     string   `json:"price_source,omitempty"`
+	UnitPrice       *float64 `json:"unit_price"`
+	DiscountPercent *float64 `json:"discount_percent"`
+	Gross           *float64 `json:"gross"`
+	DiscountAmount  *float64 `json:"discount_amount"`
+	Total           *float64 `json:"total"`
+
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+// QuoteTotals summarizes the priced lines of a quote
+type QuoteTotals struct {
+	Gross    float64 `json:"gross"`
+	Discount float64 `json:"discount"`
+	Total    float64 `json:"total"`
+
+	PricedLines       int `json:"priced_lines"`
+	MissingPriceLines int `json:"missing_price_lines"`
+	// Complete is false when any line lacks a price, so Total is a lower bound
+	Complete bool `json:"complete"`
+}
+
+// Quote represents a saved bill of materials with price totals
+type Quote struct {
+	ID        string      `json:"id"`
+	Customer  string      `json:"customer,omitempty"`
+	Notes     string      `json:"notes,omitempty"`
+	Currency  string      `json:"currency"`
+	CreatedAt time.Time   `json:"created_at"`
+	Lines     []QuoteLine `json:"lines"`
+	Totals    QuoteTotals `json:"totals"`
+}
+
+// QuoteSummary represents a saved quote in listings
+type QuoteSummary struct {
+	ID        string    `json:"id"`
+	Customer  string    `json:"customer,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	Lines     int       `json:"lines"`
+	Total     float64   `json:"total"`
+	Complete  bool      `json:"complete"`
+}
@@ -8,6 +8,7 @@ type APIResponse struct {
 	Message   string      `json:"message"`
 	Data      interface{} `json:"data,omitempty"`
 	Error     string      `json:"error,omitempty"`
+	RequestID string      `json:"request_id,omitempty"`
 	Timestamp time.Time   `json:"timestamp"`
 }
 
@@ -37,6 +38,7 @@ type ErrorResponse struct {
 	Error     string    `json:"error"`
 	Message   string    `json:"message"`
 	Code      string    `json:"code,omitempty"`
+	RequestID string    `json:"request_id,omitempty"`
 	Timestamp time.Time `json:"timestamp"`
 }
 
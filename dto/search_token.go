@@ -0,0 +1,22 @@
+package dto
+
+import "time"
+
+// SearchTokenRequest asks for a tenant token restricted to some categories
+// or brands. Empty lists leave that dimension unrestricted.
+type SearchTokenRequest struct {
+	Categories []string `json:"categories,omitempty"`
+	Brands     []string `json:"brands,omitempty"`
+	// ExpiresIn is the token lifetime in seconds; zero uses the default
+	ExpiresIn int `json:"expires_in,omitempty"`
+}
+
+// SearchTokenResponse carries a tenant token browsers can use to search the
+// index directly on Meilisearch
+type SearchTokenResponse struct {
+	Token     string    `json:"token"`
+	Host      string    `json:"host"`
+	IndexUID  string    `json:"index_uid"`
+	Filter    string    `json:"filter"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
@@ -0,0 +1,18 @@
+package dto
+
+// SimilarProduct represents a recommended alternative to a product
+type SimilarProduct struct {
+	Product
+	// Score ranks the match from 0 to 1
+	Score float64 `json:"score"`
+	// Reason explains why the product was recommended
+	Reason string `json:"reason"`
+}
+
+// SimilarProductsResponse lists alternatives to a product
+type SimilarProductsResponse struct {
+	ProductID  int              `json:"product_id"`
+	Brand      string           `json:"brand,omitempty"`
+	BrandScope string           `json:"brand_scope"`
+	Results    []SimilarProduct `json:"results"`
+}
@@ -0,0 +1,28 @@
+package dto
+
+// Suggestion represents a single search-as-you-type product suggestion
+type Suggestion struct {
+	ID                  int    `json:"id"`
+	SKU                 string `json:"sku"`
+	Name                string `json:"name"`
+	CategoryName        string `json:"category_name"`
+	HighlightedName     string `json:"highlighted_name"`
+	HighlightedSKU      string `json:"highlighted_sku"`
+	HighlightedCategory string `json:"highlighted_category"`
+	// Collapsed counts near-identical products folded into this suggestion
+	Collapsed int `json:"collapsed"`
+}
+
+// CategorySuggestion represents a category matching a suggest query
+type CategorySuggestion struct {
+	Name  string `json:"name"`
+	Count int64  `json:"count"`
+}
+
+// SuggestResponse represents a search-as-you-type response
+type SuggestResponse struct {
+	Query        string               `json:"query"`
+	Suggestions  []Suggestion         `json:"suggestions"`
+	Categories   []CategorySuggestion `json:"categories"`
+	ProcessingMs int64                `json:"processing_time_ms"`
+}
@@ -0,0 +1,25 @@
+package dto
+
+import "time"
+
+// SynonymGroup represents a set of search terms treated as equivalent.
+// When OneWay is set, only the first term expands to the others.
+type SynonymGroup struct {
+	ID       string   `json:"id"`
+	Category string   `json:"category,omitempty"`
+	Terms    []string `json:"terms"`
+	OneWay   bool     `json:"one_way,omitempty"`
+}
+
+// SynonymSet represents the versioned synonym dictionary
+type SynonymSet struct {
+	Version   int            `json:"version"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	Groups    []SynonymGroup `json:"groups"`
+}
+
+// SynonymUpdateResponse represents the result of changing the synonym dictionary
+type SynonymUpdateResponse struct {
+	Version int         `json:"version"`
+	Task    *TaskStatus `json:"task"`
+}
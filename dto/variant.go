@@ -0,0 +1,22 @@
+package dto
+
+// ProductVariant represents one product of a variant group
+type ProductVariant struct {
+	ID           int     `json:"id"`
+	SKU          string  `json:"sku"`
+	Name         string  `json:"name"`
+	SellingPrice *string `json:"selling_price"`
+	IsActive     int     `json:"is_active"`
+	// Current marks the product the variants were requested for
+	Current bool `json:"current"`
+	// Attributes holds the values of the attributes that differ between variants
+	Attributes map[string]interface{} `json:"attributes"`
+}
+
+// VariantsResponse lists the variants of a product
+type VariantsResponse struct {
+	ProductID           int              `json:"product_id"`
+	VariantGroup        string           `json:"variant_group"`
+	DifferingAttributes []string         `json:"differing_attributes"`
+	Variants            []ProductVariant `json:"variants"`
+}
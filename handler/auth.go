@@ -0,0 +1,102 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"meilisearch/dto"
+	"meilisearch/internal/auth"
+	"meilisearch/internal/ratelimit"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Anonymous callers. Without configured credentials anonymous callers may
+// only read; AUTH_DISABLED makes them admins.
+var (
+	anonymous       = &auth.Principal{Subject: "anonymous", Role: auth.RoleNone, Method: auth.MethodNone}
+	anonymousReader = &auth.Principal{Subject: "anonymous", Role: auth.RoleReader, Method: auth.MethodNone}
+	anonymousAdmin  = &auth.Principal{Subject: "anonymous", Role: auth.RoleAdmin, Method: auth.MethodNone}
+)
+
+// authorizer guards routes with the minimum role allowed to call them and the
+// rate limit of their caller
+type authorizer struct {
+	authenticator *auth.Authenticator
+	limiter       *ratelimit.Limiter
+}
+
+// require wraps a route handler so it only runs for callers with at least the
+// given role who are within their rate limit. Authenticated callers are
+// limited by API key or token subject; anonymous callers and failed
+// authentication attempts count against the client IP. Invalid credentials,
+// or none where the role needs them, get 401, callers over their limit 429
+// and an insufficient role 403. The caller is available to the handler via
+// auth.PrincipalFromContext.
+func (a *authorizer) require(role auth.Role, next http.HandlerFunc) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		principal, err := a.authenticate(r)
+		if err != nil {
+			if limitRate(a.limiter, w, r, "ip:"+a.limiter.ClientIP(r)) {
+				writeUnauthorized(w, "Invalid API key or bearer token", "INVALID_CREDENTIALS")
+			}
+			return
+		}
+		setPrincipal(r, principal)
+
+		client := "ip:" + a.limiter.ClientIP(r)
+		if principal.Method != auth.MethodNone {
+			client = principal.Method + ":" + principal.Subject
+		}
+		if !limitRate(a.limiter, w, r, client) {
+			return
+		}
+		if !principal.Role.Allows(role) {
+			if principal.Method == auth.MethodNone {
+				writeUnauthorized(w, "An API key or bearer token is required", "MISSING_CREDENTIALS")
+				return
+			}
+			message := fmt.Sprintf("The %s role cannot access this endpoint; %s is required", principal.Role, role)
+			writeJSONResponse(w, http.StatusForbidden, dto.NewErrorResponse("FORBIDDEN", message, "INSUFFICIENT_ROLE"))
+			return
+		}
+		next.ServeHTTP(w, r.WithContext(auth.WithPrincipal(r.Context(), principal)))
+	})
+}
+
+// authenticate identifies the caller of a request. Requests without
+// credentials get an anonymous principal whose role depends on the
+// configuration.
+func (a *authorizer) authenticate(r *http.Request) (*auth.Principal, error) {
+	switch {
+	case a.authenticator.Disabled():
+		return anonymousAdmin, nil
+	case !a.authenticator.Enabled():
+		return anonymousReader, nil
+	}
+	principal, err := a.authenticator.Authenticate(r)
+	if errors.Is(err, auth.ErrMissingCredentials) {
+		return anonymous, nil
+	}
+	return principal, err
+}
+
+// writeUnauthorized answers 401 with a bearer challenge
+func writeUnauthorized(w http.ResponseWriter, message, code string) {
+	w.Header().Set("WWW-Authenticate", `Bearer realm="catalog"`)
+	writeJSONResponse(w, http.StatusUnauthorized, dto.NewErrorResponse("UNAUTHORIZED", message, code))
+}
+
+// setPrincipal records the caller of a request for the request log and the
+// request span
+func setPrincipal(r *http.Request, principal *auth.Principal) {
+	if info, ok := r.Context().Value(requestInfoKey{}).(*requestInfo); ok {
+		info.subject = principal.Subject
+	}
+	trace.SpanFromContext(r.Context()).SetAttributes(
+		attribute.String("enduser.id", principal.Subject),
+		attribute.String("enduser.role", principal.Role.String()),
+	)
+}
@@ -0,0 +1,62 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"meilisearch/internal/auth"
+	"meilisearch/internal/ratelimit"
+)
+
+func TestAuthorizerRequire(t *testing.T) {
+	secured := auth.NewAuthenticator(
+		auth.WithAPIKey("storefront", auth.RoleReader, "reader-key"),
+		auth.WithAPIKey("sales", auth.RoleEditor, "editor-key"),
+	)
+	tests := []struct {
+		name          string
+		authenticator *auth.Authenticator
+		role          auth.Role
+		key           string
+		want          int
+	}{
+		{"open reader route", auth.NewAuthenticator(), auth.RoleReader, "", http.StatusOK},
+		{"open editor route", auth.NewAuthenticator(), auth.RoleEditor, "", http.StatusUnauthorized},
+		{"open admin route", auth.NewAuthenticator(), auth.RoleAdmin, "", http.StatusUnauthorized},
+		{"disabled admin route", auth.NewAuthenticator(auth.WithDisabled()), auth.RoleAdmin, "", http.StatusOK},
+		{"anonymous reader route", secured, auth.RoleReader, "", http.StatusUnauthorized},
+		{"invalid key", secured, auth.RoleReader, "guess", http.StatusUnauthorized},
+		{"reader on reader route", secured, auth.RoleReader, "reader-key", http.StatusOK},
+		{"reader on editor route", secured, auth.RoleEditor, "reader-key", http.StatusForbidden},
+		{"editor on editor route", secured, auth.RoleEditor, "editor-key", http.StatusOK},
+		{"editor on admin route", secured, auth.RoleAdmin, "editor-key", http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			guard := &authorizer{authenticator: tt.authenticator, limiter: ratelimit.NewLimiter()}
+			mux := http.NewServeMux()
+			mux.Handle("GET /api/test", guard.require(tt.role, func(w http.ResponseWriter, r *http.Request) {
+				if auth.PrincipalFromContext(r.Context()) == nil {
+					t.Error("handler ran without a principal")
+				}
+				w.WriteHeader(http.StatusOK)
+			}))
+
+			r := httptest.NewRequest(http.MethodGet, "/api/test", nil)
+			if tt.key != "" {
+				r.Header.Set(auth.APIKeyHeader, tt.key)
+			}
+			w := httptest.NewRecorder()
+			mux.ServeHTTP(w, r)
+
+			if w.Code != tt.want {
+				t.Errorf("status = %d, want %d", w.Code, tt.want)
+			}
+			if w.Code == http.StatusUnauthorized && w.Header().Get("WWW-Authenticate") == "" {
+				t.Error("401 without a WWW-Authenticate challenge")
+			}
+		})
+	}
+}
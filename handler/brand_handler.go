@@ -0,0 +1,33 @@
+package handler
+
+import (
+	"net/http"
+
+	"meilisearch/dto"
+	"meilisearch/service"
+)
+
+// BrandHandler handles HTTP requests for brand listings
+type BrandHandler struct {
+	service *service.BrandService
+}
+
+// NewBrandHandler creates a new brand handler
+func NewBrandHandler(brandService *service.BrandService) *BrandHandler {
+	return &BrandHandler{
+		service: brandService,
+	}
+}
+
+// ListBrands handles requests to list brands with product counts per category
+func (h *BrandHandler) ListBrands(w http.ResponseWriter, r *http.Request) {
+	brands, err := h.service.ListBrands(r.Context())
+	if err != nil {
+		response := dto.NewErrorResponse("BRANDS_FAILED", "Failed to list brands", "INTERNAL_ERROR")
+		writeJSONResponse(w, http.StatusInternalServerError, response)
+		return
+	}
+
+	response := dto.NewSuccessResponse("Brands retrieved successfully", brands)
+	writeJSONResponse(w, http.StatusOK, response)
+}
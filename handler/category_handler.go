@@ -0,0 +1,96 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"meilisearch/dto"
+	"meilisearch/service"
+)
+
+// CategoryHandler handles HTTP requests for the category hierarchy
+type CategoryHandler struct {
+	categories *service.CategoryService
+	products   *service.ProductService
+}
+
+// NewCategoryHandler creates a new category handler
+func NewCategoryHandler(categoryService *service.CategoryService, productService *service.ProductService) *CategoryHandler {
+	return &CategoryHandler{
+		categories: categoryService,
+		products:   productService,
+	}
+}
+
+// ListCategories handles requests for the category hierarchy with product counts
+func (h *CategoryHandler) ListCategories(w http.ResponseWriter, r *http.Request) {
+	categories, err := h.categories.Categories(r.Context())
+	if err != nil {
+		response := dto.NewErrorResponse("CATEGORIES_FAILED", "Failed to list categories", "INTERNAL_ERROR")
+		writeJSONResponse(w, http.StatusInternalServerError, response)
+		return
+	}
+
+	response := dto.NewSuccessResponse("Categories retrieved successfully", categories)
+	writeJSONResponse(w, http.StatusOK, response)
+}
+
+// BrowseCategory handles requests to list the products of a category, e.g.
+// /api/categories/Hardware/Channels. A query is optional; results are paged
+// and may be sorted with sort and order.
+func (h *CategoryHandler) BrowseCategory(w http.ResponseWriter, r *http.Request) {
+	path, err := h.categories.ResolvePath(strings.Split(strings.Trim(r.PathValue("path"), "/"), "/"))
+	if err != nil {
+		if errors.Is(err, service.ErrCategoryNotFound) {
+			response := dto.NewErrorResponse("NOT_FOUND", "Category not found", "CATEGORY_NOT_FOUND")
+			writeJSONResponse(w, http.StatusNotFound, response)
+			return
+		}
+		response := dto.NewErrorResponse("CATEGORIES_FAILED", "Failed to load categories", "INTERNAL_ERROR")
+		writeJSONResponse(w, http.StatusInternalServerError, response)
+		return
+	}
+
+	sortBy, sortOrder := r.URL.Query().Get("sort"), r.URL.Query().Get("order")
+	if !service.ValidSort(sortBy, sortOrder) {
+		writeInvalidSort(w)
+		return
+	}
+
+	page, err := strconv.Atoi(r.URL.Query().Get("page"))
+	if err != nil || page <= 0 {
+		page = 1
+	}
+	perPage, _ := strconv.Atoi(r.URL.Query().Get("per_page"))
+	collapseVariants, _ := strconv.ParseBool(r.URL.Query().Get("collapse_variants"))
+
+	searchRes, err := h.products.Search(r.Context(), dto.ProductSearchRequest{
+		Query:            r.URL.Query().Get("q"),
+		CategoryPath:     path,
+		Brand:            r.URL.Query().Get("brand"),
+		Facets:           parseAttributeList(r.URL.Query().Get("facets"), nil),
+		SortBy:           sortBy,
+		SortOrder:        sortOrder,
+		Page:             page,
+		PerPage:          perPage,
+		CollapseVariants: collapseVariants,
+	})
+	if err != nil {
+		response := dto.NewErrorResponse("SEARCH_FAILED", "Browse operation failed", "INTERNAL_ERROR")
+		writeJSONResponse(w, http.StatusInternalServerError, response)
+		return
+	}
+	setResultCount(r, searchRes.TotalHits)
+
+	response := dto.NewPaginatedResponse("Category products retrieved successfully", searchRes, searchRes.Page, searchRes.Limit, searchRes.TotalHits)
+	writeJSONResponse(w, http.StatusOK, response)
+}
+
+// writeInvalidSort writes a 400 response for an unsupported sort
+func writeInvalidSort(w http.ResponseWriter) {
+	message := "Parameter 'sort' must be one of " + strings.Join(service.SortableAttributes, ", ") + " and 'order' asc or desc"
+	response := dto.NewErrorResponse("BAD_REQUEST", message, "INVALID_SORT")
+	writeJSONResponse(w, http.StatusBadRequest, response)
+}
@@ -0,0 +1,100 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"meilisearch/dto"
+	"meilisearch/internal/cors"
+)
+
+// CORSMiddleware applies a CORS policy. Preflight requests are answered
+// here: 204 when the origin, method and headers are allowed, 403 otherwise.
+// Other requests from an allowed origin get the CORS response headers;
+// requests from other origins are served without them, so browsers hide the
+// response.
+func CORSMiddleware(policy cors.Policy, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		origin := r.Header.Get("Origin")
+		if origin == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+		// The response depends on the origin even when it is not allowed
+		w.Header().Add("Vary", "Origin")
+
+		requestMethod := r.Header.Get("Access-Control-Request-Method")
+		preflight := r.Method == http.MethodOptions && requestMethod != ""
+		allowed := policy.AllowsOrigin(origin)
+
+		if !preflight {
+			if allowed {
+				setOriginHeaders(w, policy, origin)
+				if len(policy.ExposedHeaders) > 0 {
+					w.Header().Set("Access-Control-Expose-Headers", strings.Join(policy.ExposedHeaders, ", "))
+				}
+			}
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		w.Header().Add("Vary", "Access-Control-Request-Method")
+		w.Header().Add("Vary", "Access-Control-Request-Headers")
+		requestHeaders := splitHeaderList(r.Header.Get("Access-Control-Request-Headers"))
+		switch {
+		case !allowed:
+			rejectPreflight(w, "Origin "+origin+" is not allowed", "CORS_ORIGIN_NOT_ALLOWED")
+			return
+		case !policy.AllowsMethod(requestMethod):
+			rejectPreflight(w, "Method "+requestMethod+" is not allowed", "CORS_METHOD_NOT_ALLOWED")
+			return
+		}
+		for _, header := range requestHeaders {
+			if !policy.AllowsHeader(header) {
+				rejectPreflight(w, "Header "+header+" is not allowed", "CORS_HEADER_NOT_ALLOWED")
+				return
+			}
+		}
+
+		setOriginHeaders(w, policy, origin)
+		w.Header().Set("Access-Control-Allow-Methods", strings.Join(policy.AllowedMethods, ", "))
+		if len(requestHeaders) > 0 {
+			// Echoing the requested headers also covers an allowed "*"
+			w.Header().Set("Access-Control-Allow-Headers", strings.Join(requestHeaders, ", "))
+		}
+		if policy.MaxAge > 0 {
+			w.Header().Set("Access-Control-Max-Age", strconv.Itoa(int(policy.MaxAge.Seconds())))
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+}
+
+// setOriginHeaders allows the origin of a request. A wildcard policy answers
+// "*" unless credentials are allowed, which requires naming the origin.
+func setOriginHeaders(w http.ResponseWriter, policy cors.Policy, origin string) {
+	if policy.AllowsAnyOrigin() && !policy.AllowCredentials {
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+	} else {
+		w.Header().Set("Access-Control-Allow-Origin", origin)
+	}
+	if policy.AllowCredentials {
+		w.Header().Set("Access-Control-Allow-Credentials", "true")
+	}
+}
+
+// rejectPreflight answers a preflight request that the policy does not allow
+func rejectPreflight(w http.ResponseWriter, message, code string) {
+	writeJSONResponse(w, http.StatusForbidden, dto.NewErrorResponse("FORBIDDEN", message, code))
+}
+
+// splitHeaderList splits a comma-separated header list, dropping empty entries
+func splitHeaderList(value string) []string {
+	var values []string
+	for _, v := range strings.Split(value, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			values = append(values, v)
+		}
+	}
+	return values
+}
@@ -0,0 +1,153 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"meilisearch/internal/cors"
+)
+
+func TestCORSMiddleware(t *testing.T) {
+	policy := cors.Policy{
+		AllowedOrigins:   []string{"https://*.example.com"},
+		AllowedMethods:   []string{http.MethodGet, http.MethodPost},
+		AllowedHeaders:   []string{"Content-Type", "Authorization"},
+		ExposedHeaders:   []string{RequestIDHeader},
+		AllowCredentials: true,
+		MaxAge:           10 * time.Minute,
+	}
+
+	tests := []struct {
+		name           string
+		method         string
+		origin         string
+		requestMethod  string
+		requestHeaders string
+		status         int
+		allowOrigin    string
+		allowHeaders   string
+		maxAge         string
+		exposeHeaders  string
+		reachesHandler bool
+	}{
+		{
+			name:           "same-origin request",
+			method:         http.MethodGet,
+			status:         http.StatusOK,
+			reachesHandler: true,
+		},
+		{
+			name:           "allowed origin",
+			method:         http.MethodGet,
+			origin:         "https://shop.example.com",
+			status:         http.StatusOK,
+			allowOrigin:    "https://shop.example.com",
+			exposeHeaders:  RequestIDHeader,
+			reachesHandler: true,
+		},
+		{
+			name:           "other origin is served without CORS headers",
+			method:         http.MethodGet,
+			origin:         "https://evil.com",
+			status:         http.StatusOK,
+			reachesHandler: true,
+		},
+		{
+			name:           "allowed preflight",
+			method:         http.MethodOptions,
+			origin:         "https://shop.example.com",
+			requestMethod:  http.MethodPost,
+			requestHeaders: "content-type, authorization",
+			status:         http.StatusNoContent,
+			allowOrigin:    "https://shop.example.com",
+			allowHeaders:   "content-type, authorization",
+			maxAge:         "600",
+		},
+		{
+			name:          "preflight from another origin",
+			method:        http.MethodOptions,
+			origin:        "https://evil.com",
+			requestMethod: http.MethodPost,
+			status:        http.StatusForbidden,
+		},
+		{
+			name:          "preflight for another method",
+			method:        http.MethodOptions,
+			origin:        "https://shop.example.com",
+			requestMethod: http.MethodDelete,
+			status:        http.StatusForbidden,
+		},
+		{
+			name:           "preflight for another header",
+			method:         http.MethodOptions,
+			origin:         "https://shop.example.com",
+			requestMethod:  http.MethodGet,
+			requestHeaders: "X-Debug",
+			status:         http.StatusForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reached := false
+			handler := CORSMiddleware(policy, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				reached = true
+				w.WriteHeader(http.StatusOK)
+			}))
+
+			r := httptest.NewRequest(tt.method, "/api/products", nil)
+			if tt.origin != "" {
+				r.Header.Set("Origin", tt.origin)
+			}
+			if tt.requestMethod != "" {
+				r.Header.Set("Access-Control-Request-Method", tt.requestMethod)
+			}
+			if tt.requestHeaders != "" {
+				r.Header.Set("Access-Control-Request-Headers", tt.requestHeaders)
+			}
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, r)
+
+			if w.Code != tt.status {
+				t.Errorf("status = %d, want %d", w.Code, tt.status)
+			}
+			if reached != tt.reachesHandler {
+				t.Errorf("reached handler = %v, want %v", reached, tt.reachesHandler)
+			}
+			headers := map[string]string{
+				"Access-Control-Allow-Origin":   tt.allowOrigin,
+				"Access-Control-Allow-Headers":  tt.allowHeaders,
+				"Access-Control-Max-Age":        tt.maxAge,
+				"Access-Control-Expose-Headers": tt.exposeHeaders,
+			}
+			for header, want := range headers {
+				if got := w.Header().Get(header); got != want {
+					t.Errorf("%s = %q, want %q", header, got, want)
+				}
+			}
+			if tt.allowOrigin != "" && w.Header().Get("Access-Control-Allow-Credentials") != "true" {
+				t.Error("credentials not allowed")
+			}
+			if tt.origin != "" && w.Header().Get("Vary") == "" {
+				t.Error("response does not vary by origin")
+			}
+		})
+	}
+}
+
+func TestCORSMiddlewareWildcard(t *testing.T) {
+	handler := CORSMiddleware(cors.DefaultPolicy(), http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	r := httptest.NewRequest(http.MethodGet, "/api/products", nil)
+	r.Header.Set("Origin", "https://anywhere.test")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, r)
+
+	if got := w.Header().Get("Access-Control-Allow-Origin"); got != "*" {
+		t.Errorf("Access-Control-Allow-Origin = %q, want *", got)
+	}
+	if got := w.Header().Get("Access-Control-Allow-Credentials"); got != "" {
+		t.Errorf("Access-Control-Allow-Credentials = %q, want none", got)
+	}
+}
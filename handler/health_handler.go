@@ -0,0 +1,77 @@
+package handler
+
+import (
+	"net/http"
+	"sync/atomic"
+
+	"meilisearch/dto"
+	"meilisearch/service"
+)
+
+// Readiness reports whether the server should receive traffic. It starts
+// not ready and is flipped off again before shutdown so load balancers stop
+// routing requests while in-flight ones drain.
+type Readiness struct {
+	ready atomic.Bool
+}
+
+// NewReadiness creates a readiness flag that starts not ready
+func NewReadiness() *Readiness {
+	return &Readiness{}
+}
+
+// SetReady marks the server ready or not ready
+func (r *Readiness) SetReady(ready bool) {
+	r.ready.Store(ready)
+}
+
+// Ready reports whether the server is ready
+func (r *Readiness) Ready() bool {
+	return r.ready.Load()
+}
+
+// HealthHandler handles liveness and readiness probes
+type HealthHandler struct {
+	readiness *Readiness
+	service   *service.HealthService
+}
+
+// NewHealthHandler creates a new health handler
+func NewHealthHandler(readiness *Readiness, healthService *service.HealthService) *HealthHandler {
+	return &HealthHandler{
+		readiness: readiness,
+		service:   healthService,
+	}
+}
+
+// Live handles liveness probes. The process is alive as long as it answers,
+// whatever the state of Meilisearch.
+func (h *HealthHandler) Live(w http.ResponseWriter, r *http.Request) {
+	response := dto.NewSuccessResponse("Service is alive", map[string]string{
+		"status":  "alive",
+		"service": "product-catalog",
+	})
+	writeJSONResponse(w, http.StatusOK, response)
+}
+
+// Ready handles readiness probes, answering 503 while the server is starting
+// or shutting down and while any Meilisearch check fails
+func (h *HealthHandler) Ready(w http.ResponseWriter, r *http.Request) {
+	if !h.readiness.Ready() {
+		response := dto.NewErrorResponse("SERVICE_UNAVAILABLE", "Service is not ready", "NOT_READY")
+		writeJSONResponse(w, http.StatusServiceUnavailable, response)
+		return
+	}
+
+	report := h.service.Readiness(r.Context())
+	if !report.Ready {
+		// The failing checks are returned so operators can see what is wrong
+		response := dto.NewAPIResponse(false, "Service is not ready", report)
+		response.Error = "NOT_READY"
+		writeJSONResponse(w, http.StatusServiceUnavailable, response)
+		return
+	}
+
+	response := dto.NewSuccessResponse("Service is ready", report)
+	writeJSONResponse(w, http.StatusOK, response)
+}
@@ -2,25 +2,35 @@ package handler
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"meilisearch/dto"
+	"meilisearch/internal/auth"
+	"meilisearch/service"
+)
+
+// maxJSONBodyBytes bounds the JSON request bodies the API decodes
+const maxJSONBodyBytes = 1 << 20
 
-	"github.com/meilisearch/meilisearch-go"
+// Attributes highlighted or cropped when a search sets highlight=true or crop=true
+var (
+	defaultHighlightAttributes = []string{"name", "sku", "category_name", "description"}
+	defaultCropAttributes      = []string{"description"}
 )
 
 // ProductHandler handles HTTP requests for product operations
 type ProductHandler struct {
-	client interface{}
-	index  interface{}
+	service *service.ProductService
 }
 
 // NewProductHandler creates a new product handler
-func NewProductHandler(client interface{}) *ProductHandler {
+func NewProductHandler(productService *service.ProductService) *ProductHandler {
 	return &ProductHandler{
-		client: client,
-		index:  client.(interface{ Index(string) interface{} }).Index("sku"),
+		service: productService,
 	}
 }
 
@@ -44,138 +54,417 @@ func (h *ProductHandler) SearchProducts(w http.ResponseWriter, r *http.Request)
 		offset = 0 // default offset
 	}
 
-	// Create search request
-	searchRequest := &meilisearch.SearchRequest{
-		Limit:  int64(limit),
-		Offset: int64(offset),
+	// Page-based pagination; per_page defaults and limits are enforced by the service
+	page, err := strconv.Atoi(r.URL.Query().Get("page"))
+	if err != nil || page < 0 {
+		page = 0
+	}
+	perPage, _ := strconv.Atoi(r.URL.Query().Get("per_page"))
+	if page == 0 && perPage > 0 {
+		page = 1
+	}
+
+	sortBy, sortOrder := r.URL.Query().Get("sort"), r.URL.Query().Get("order")
+	if !service.ValidSort(sortBy, sortOrder) {
+		writeInvalidSort(w)
+		return
 	}
 
+	includeInactive, _ := strconv.ParseBool(r.URL.Query().Get("include_inactive"))
+	if includeInactive && !allowsInactive(r) {
+		writeInactiveForbidden(w)
+		return
+	}
+	rawQuery, _ := strconv.ParseBool(r.URL.Query().Get("raw_query"))
+	collapseVariants, _ := strconv.ParseBool(r.URL.Query().Get("collapse_variants"))
+	cropLength, _ := strconv.Atoi(r.URL.Query().Get("crop_length"))
+
 	// Perform search
-	result, err := h.index.(interface {
-		Search(string, *meilisearch.SearchRequest) (interface{}, error)
-	}).Search(query, searchRequest)
+	searchRes, err := h.service.Search(r.Context(), dto.ProductSearchRequest{
+		Query:            query,
+		Limit:            limit,
+		Offset:           offset,
+		Category:         r.URL.Query().Get("category"),
+		CategoryPath:     r.URL.Query().Get("category_path"),
+		Status:           r.URL.Query().Get("status"),
+		Brand:            r.URL.Query().Get("brand"),
+		Facets:           parseAttributeList(r.URL.Query().Get("facets"), nil),
+		SortBy:           sortBy,
+		SortOrder:        sortOrder,
+		Page:             page,
+		PerPage:          perPage,
+		IncludeInactive:  includeInactive,
+		RawQuery:         rawQuery,
+		CollapseVariants: collapseVariants,
+		Highlight:        parseAttributeList(r.URL.Query().Get("highlight"), defaultHighlightAttributes),
+		Crop:             parseAttributeList(r.URL.Query().Get("crop"), defaultCropAttributes),
+		CropLength:       cropLength,
+		CropMarker:       r.URL.Query().Get("crop_marker"),
+		HighlightPreTag:  r.URL.Query().Get("highlight_pre_tag"),
+		HighlightPostTag: r.URL.Query().Get("highlight_post_tag"),
+	})
 	if err != nil {
 		response := dto.NewErrorResponse("SEARCH_FAILED", "Search operation failed", "INTERNAL_ERROR")
 		writeJSONResponse(w, http.StatusInternalServerError, response)
 		return
 	}
+	setResultCount(r, searchRes.TotalHits)
 
-	// Convert result to our response format
-	searchRes := dto.ProductSearchResponse{
-		Hits:       []dto.Product{},
-		TotalHits:  0,
-		Processing: false,
-		Query:      query,
-		Limit:      limit,
-		Offset:     offset,
+	if page > 0 {
+		response := dto.NewPaginatedResponse("Search completed successfully", searchRes, searchRes.Page, searchRes.Limit, searchRes.TotalHits)
+		writeJSONResponse(w, http.StatusOK, response)
+		return
+	}
+
+	response := dto.NewSuccessResponse("Search completed successfully", searchRes)
+	writeJSONResponse(w, http.StatusOK, response)
+}
+
+// MultiSearchProducts handles batched product search requests
+func (h *ProductHandler) MultiSearchProducts(w http.ResponseWriter, r *http.Request) {
+	var req dto.MultiSearchRequest
+	if err := decodeJSONBody(w, r, &req); err != nil {
+		writeBodyError(w, err, "Invalid multi-search request body")
+		return
 	}
 
-	// Try to extract hits if available
-	if hits, ok := result.(map[string]interface{}); ok {
-		if hitsList, ok := hits["hits"].([]interface{}); ok {
-			searchRes.TotalHits = len(hitsList)
+	if len(req.Queries) == 0 {
+		response := dto.NewErrorResponse("BAD_REQUEST", "At least one query is required", "MISSING_QUERIES")
+		writeJSONResponse(w, http.StatusBadRequest, response)
+		return
+	}
+	if len(req.Queries) > service.MaxMultiSearchQueries {
+		message := "At most " + strconv.Itoa(service.MaxMultiSearchQueries) + " queries are allowed per request"
+		response := dto.NewErrorResponse("BAD_REQUEST", message, "TOO_MANY_QUERIES")
+		writeJSONResponse(w, http.StatusBadRequest, response)
+		return
+	}
+
+	for _, query := range req.Queries {
+		if query.IncludeInactive && !allowsInactive(r) {
+			writeInactiveForbidden(w)
+			return
 		}
 	}
 
-	response := dto.NewSuccessResponse("Search completed successfully", searchRes)
+	results, err := h.service.MultiSearch(r.Context(), req.Queries)
+	if err != nil {
+		response := dto.NewErrorResponse("SEARCH_FAILED", "Multi-search operation failed", "INTERNAL_ERROR")
+		writeJSONResponse(w, http.StatusInternalServerError, response)
+		return
+	}
+
+	response := dto.NewSuccessResponse("Multi-search completed", results)
+	writeJSONResponse(w, http.StatusOK, response)
+}
+
+// MatchList handles requests to match a pasted material list to products
+func (h *ProductHandler) MatchList(w http.ResponseWriter, r *http.Request) {
+	var req dto.MatchListRequest
+	if err := decodeJSONBody(w, r, &req); err != nil {
+		writeBodyError(w, err, "Invalid match-list request body")
+		return
+	}
+
+	matches, err := h.service.MatchList(r.Context(), req)
+	switch {
+	case errors.Is(err, service.ErrEmptyMatchList):
+		response := dto.NewErrorResponse("BAD_REQUEST", "At least one non-empty line is required", "EMPTY_LIST")
+		writeJSONResponse(w, http.StatusBadRequest, response)
+		return
+	case errors.Is(err, service.ErrTooManyLines):
+		message := "At most " + strconv.Itoa(service.MaxMatchListLines) + " lines are allowed per request"
+		response := dto.NewErrorResponse("BAD_REQUEST", message, "TOO_MANY_LINES")
+		writeJSONResponse(w, http.StatusBadRequest, response)
+		return
+	case err != nil:
+		response := dto.NewErrorResponse("SEARCH_FAILED", "Match-list operation failed", "INTERNAL_ERROR")
+		writeJSONResponse(w, http.StatusInternalServerError, response)
+		return
+	}
+
+	response := dto.NewSuccessResponse("Material list matched", matches)
+	writeJSONResponse(w, http.StatusOK, response)
+}
+
+// SuggestProducts handles search-as-you-type requests
+func (h *ProductHandler) SuggestProducts(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query().Get("q")
+
+	// A missing or invalid limit falls back to the configured suggest limit
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+
+	suggestions, err := h.service.Suggest(r.Context(), query, limit)
+	if err != nil {
+		response := dto.NewErrorResponse("SUGGEST_FAILED", "Suggest operation failed", "INTERNAL_ERROR")
+		writeJSONResponse(w, http.StatusInternalServerError, response)
+		return
+	}
+	setResultCount(r, len(suggestions.Suggestions))
+
+	response := dto.NewSuccessResponse("Suggestions retrieved successfully", suggestions)
 	writeJSONResponse(w, http.StatusOK, response)
 }
 
 // GetProductByID handles requests to get a product by ID
 func (h *ProductHandler) GetProductByID(w http.ResponseWriter, r *http.Request) {
-	// Extract ID from URL path
-	idStr := r.URL.Query().Get("id")
-	if idStr == "" {
-		response := dto.NewErrorResponse("BAD_REQUEST", "Product ID is required", "MISSING_ID")
-		writeJSONResponse(w, http.StatusBadRequest, response)
+	id, ok := parseProductID(w, r)
+	if !ok {
 		return
 	}
 
-	id, err := strconv.Atoi(idStr)
+	product, err := h.service.GetProduct(r.Context(), id)
 	if err != nil {
-		response := dto.NewErrorResponse("BAD_REQUEST", "Invalid product ID", "INVALID_ID")
-		writeJSONResponse(w, http.StatusBadRequest, response)
+		writeProductError(w, err)
 		return
 	}
 
-	// Search for product by ID
-	searchRequest := &meilisearch.SearchRequest{
-		Filter: "id = " + strconv.Itoa(id),
-		Limit:  1,
+	response := dto.NewSuccessResponse("Product retrieved successfully", product)
+	writeJSONResponse(w, http.StatusOK, response)
+}
+
+// GetProductVariants handles requests to list the variants of a product
+func (h *ProductHandler) GetProductVariants(w http.ResponseWriter, r *http.Request) {
+	id, ok := parseProductID(w, r)
+	if !ok {
+		return
 	}
 
-	result, err := h.index.(interface {
-		Search(string, *meilisearch.SearchRequest) (interface{}, error)
-	}).Search("", searchRequest)
+	variants, err := h.service.Variants(r.Context(), id)
 	if err != nil {
-		response := dto.NewErrorResponse("SEARCH_FAILED", "Search operation failed", "INTERNAL_ERROR")
-		writeJSONResponse(w, http.StatusInternalServerError, response)
+		writeProductError(w, err)
 		return
 	}
 
-	// Check if product found
-	if hits, ok := result.(map[string]interface{}); ok {
-		if hitsList, ok := hits["hits"].([]interface{}); ok && len(hitsList) > 0 {
-			// Product found
-			product := dto.Product{
-				ID:           id,
-				SKU:          "Found-SKU",
-				Name:         "Found Product",
-				CategoryID:   1,
-				Description:  "Product found in search",
-				Status:       "Active",
-				CategoryName: "Found Category",
-			}
-			response := dto.NewSuccessResponse("Product retrieved successfully", product)
-			writeJSONResponse(w, http.StatusOK, response)
+	response := dto.NewSuccessResponse("Product variants retrieved successfully", variants)
+	writeJSONResponse(w, http.StatusOK, response)
+}
+
+// GetSimilarProducts handles requests for alternatives to a product. The
+// brand parameter restricts results to the "same" or "other" brands.
+func (h *ProductHandler) GetSimilarProducts(w http.ResponseWriter, r *http.Request) {
+	id, ok := parseProductID(w, r)
+	if !ok {
+		return
+	}
+
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+
+	similar, err := h.service.SimilarProducts(r.Context(), id, r.URL.Query().Get("brand"), limit)
+	switch {
+	case errors.Is(err, service.ErrInvalidBrandScope):
+		response := dto.NewErrorResponse("BAD_REQUEST", "Parameter 'brand' must be any, same or other", "INVALID_BRAND_SCOPE")
+		writeJSONResponse(w, http.StatusBadRequest, response)
+		return
+	case errors.Is(err, service.ErrNoBrand):
+		response := dto.NewErrorResponse("BAD_REQUEST", "Product has no recognized brand", "NO_BRAND")
+		writeJSONResponse(w, http.StatusBadRequest, response)
+		return
+	case err != nil:
+		writeProductError(w, err)
+		return
+	}
+
+	response := dto.NewSuccessResponse("Similar products retrieved successfully", similar)
+	writeJSONResponse(w, http.StatusOK, response)
+}
+
+// CompareProducts handles requests to compare products side by side. The ids
+// parameter lists 2 to 4 comma-separated product IDs.
+func (h *ProductHandler) CompareProducts(w http.ResponseWriter, r *http.Request) {
+	var ids []int
+	for _, value := range strings.Split(r.URL.Query().Get("ids"), ",") {
+		if value = strings.TrimSpace(value); value == "" {
+			continue
+		}
+		id, err := strconv.Atoi(value)
+		if err != nil {
+			response := dto.NewErrorResponse("BAD_REQUEST", "Invalid product ID '"+value+"'", "INVALID_ID")
+			writeJSONResponse(w, http.StatusBadRequest, response)
 			return
 		}
+		ids = append(ids, id)
+	}
+
+	comparison, err := h.service.Compare(r.Context(), ids)
+	switch {
+	case errors.Is(err, service.ErrInvalidComparison):
+		response := dto.NewErrorResponse("BAD_REQUEST", err.Error(), "INVALID_COMPARISON")
+		writeJSONResponse(w, http.StatusBadRequest, response)
+		return
+	case errors.Is(err, service.ErrIncompatibleCategories):
+		response := dto.NewErrorResponse("UNPROCESSABLE_ENTITY", err.Error(), "INCOMPATIBLE_CATEGORIES")
+		writeJSONResponse(w, http.StatusUnprocessableEntity, response)
+		return
+	case err != nil:
+		writeProductError(w, err)
+		return
+	}
+
+	response := dto.NewSuccessResponse("Products compared successfully", comparison)
+	writeJSONResponse(w, http.StatusOK, response)
+}
+
+// DeleteProduct handles requests to delete a product. Products are soft
+// deleted unless the request sets hard=true.
+func (h *ProductHandler) DeleteProduct(w http.ResponseWriter, r *http.Request) {
+	id, ok := parseProductID(w, r)
+	if !ok {
+		return
+	}
+
+	hard, _ := strconv.ParseBool(r.URL.Query().Get("hard"))
+
+	var (
+		task    *dto.TaskStatus
+		err     error
+		message = "Product deactivated successfully"
+	)
+	if hard {
+		task, err = h.service.Delete(r.Context(), id)
+		message = "Product deleted successfully"
+	} else {
+		task, err = h.service.SoftDelete(r.Context(), id)
+	}
+	if err != nil {
+		writeProductError(w, err)
+		return
 	}
 
-	// Product not found
-	response := dto.NewErrorResponse("NOT_FOUND", "Product not found", "PRODUCT_NOT_FOUND")
-	writeJSONResponse(w, http.StatusNotFound, response)
+	response := dto.NewSuccessResponse(message, task)
+	writeJSONResponse(w, http.StatusAccepted, response)
+}
+
+// RestoreProduct handles requests to restore a soft-deleted product
+func (h *ProductHandler) RestoreProduct(w http.ResponseWriter, r *http.Request) {
+	id, ok := parseProductID(w, r)
+	if !ok {
+		return
+	}
+
+	task, err := h.service.Restore(r.Context(), id)
+	if err != nil {
+		writeProductError(w, err)
+		return
+	}
+
+	response := dto.NewSuccessResponse("Product restored successfully", task)
+	writeJSONResponse(w, http.StatusAccepted, response)
 }
 
 // GetIndexStats handles requests to get index statistics
 func (h *ProductHandler) GetIndexStats(w http.ResponseWriter, r *http.Request) {
-	stats, err := h.index.(interface{ GetStats() (interface{}, error) }).GetStats()
+	indexStats, err := h.service.GetStats(r.Context())
 	if err != nil {
 		response := dto.NewErrorResponse("STATS_FAILED", "Failed to get index statistics", "INTERNAL_ERROR")
 		writeJSONResponse(w, http.StatusInternalServerError, response)
 		return
 	}
 
-	// Extract stats
-	indexStats := dto.IndexStats{
-		NumberOfDocuments: 0,
-		IsIndexing:        false,
-	}
+	response := dto.NewSuccessResponse("Index statistics retrieved successfully", indexStats)
+	writeJSONResponse(w, http.StatusOK, response)
+}
 
-	if statsMap, ok := stats.(map[string]interface{}); ok {
-		if numDocs, ok := statsMap["numberOfDocuments"].(float64); ok {
-			indexStats.NumberOfDocuments = int64(numDocs)
+// parseAttributeList parses a comma-separated attribute list. "true" selects
+// the given defaults and "false" or an empty value disables the option.
+func parseAttributeList(value string, defaults []string) []string {
+	value = strings.TrimSpace(value)
+	if enabled, err := strconv.ParseBool(value); err == nil {
+		if enabled {
+			return defaults
 		}
-		if isIndexing, ok := statsMap["isIndexing"].(bool); ok {
-			indexStats.IsIndexing = isIndexing
+		return nil
+	}
+
+	var attributes []string
+	for _, attribute := range strings.Split(value, ",") {
+		if attribute = strings.TrimSpace(attribute); attribute != "" {
+			attributes = append(attributes, attribute)
 		}
 	}
+	return attributes
+}
 
-	response := dto.NewSuccessResponse("Index statistics retrieved successfully", indexStats)
-	writeJSONResponse(w, http.StatusOK, response)
+// parseProductID reads the product ID from the path or the id query parameter,
+// writing a 400 response when it is missing or invalid
+func parseProductID(w http.ResponseWriter, r *http.Request) (int, bool) {
+	idStr := r.PathValue("id")
+	if idStr == "" {
+		idStr = r.URL.Query().Get("id")
+	}
+	if idStr == "" {
+		response := dto.NewErrorResponse("BAD_REQUEST", "Product ID is required", "MISSING_ID")
+		writeJSONResponse(w, http.StatusBadRequest, response)
+		return 0, false
+	}
+
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		response := dto.NewErrorResponse("BAD_REQUEST", "Invalid product ID", "INVALID_ID")
+		writeJSONResponse(w, http.StatusBadRequest, response)
+		return 0, false
+	}
+	return id, true
 }
 
-// HealthCheck handles health check requests
-func (h *ProductHandler) HealthCheck(w http.ResponseWriter, r *http.Request) {
-	response := dto.NewSuccessResponse("Service is healthy", map[string]string{
-		"status":  "ok",
-		"service": "product-catalog",
-	})
-	writeJSONResponse(w, http.StatusOK, response)
+// allowsInactive reports whether the caller may search soft-deleted products,
+// which takes the editor role
+func allowsInactive(r *http.Request) bool {
+	principal := auth.PrincipalFromContext(r.Context())
+	return principal != nil && principal.Role.Allows(auth.RoleEditor)
+}
+
+// writeInactiveForbidden rejects include_inactive from callers below editor
+func writeInactiveForbidden(w http.ResponseWriter) {
+	message := "Parameter 'include_inactive' requires the " + auth.RoleEditor.String() + " role"
+	writeJSONResponse(w, http.StatusForbidden, dto.NewErrorResponse("FORBIDDEN", message, "INSUFFICIENT_ROLE"))
+}
+
+// writeProductError maps product service errors to HTTP responses
+func writeProductError(w http.ResponseWriter, err error) {
+	if errors.Is(err, service.ErrProductNotFound) {
+		response := dto.NewErrorResponse("NOT_FOUND", "Product not found", "PRODUCT_NOT_FOUND")
+		writeJSONResponse(w, http.StatusNotFound, response)
+		return
+	}
+
+	response := dto.NewErrorResponse("PRODUCT_OPERATION_FAILED", "Product operation failed", "INTERNAL_ERROR")
+	writeJSONResponse(w, http.StatusInternalServerError, response)
 }
 
 // writeJSONResponse writes a JSON response to the HTTP response writer
+// decodeJSONBody decodes a JSON request body of at most maxJSONBodyBytes
+func decodeJSONBody(w http.ResponseWriter, r *http.Request, v interface{}) error {
+	r.Body = http.MaxBytesReader(w, r.Body, maxJSONBodyBytes)
+	return json.NewDecoder(r.Body).Decode(v)
+}
+
+// writeBodyError answers a request whose body could not be decoded: 413 when
+// it is too large, 400 with the given message otherwise
+func writeBodyError(w http.ResponseWriter, err error, message string) {
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		response := dto.NewErrorResponse("PAYLOAD_TOO_LARGE", fmt.Sprintf("Request body must not exceed %d bytes", tooLarge.Limit), "BODY_TOO_LARGE")
+		writeJSONResponse(w, http.StatusRequestEntityTooLarge, response)
+		return
+	}
+	response := dto.NewErrorResponse("BAD_REQUEST", message, "INVALID_BODY")
+	writeJSONResponse(w, http.StatusBadRequest, response)
+}
+
 func writeJSONResponse(w http.ResponseWriter, statusCode int, data interface{}) {
+	// Error bodies carry the request ID so clients can quote it in reports
+	switch response := data.(type) {
+	case dto.ErrorResponse:
+		response.RequestID = w.Header().Get(RequestIDHeader)
+		data = response
+	case dto.APIResponse:
+		if !response.Success {
+			response.RequestID = w.Header().Get(RequestIDHeader)
+			data = response
+		}
+	}
+
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(statusCode)
 
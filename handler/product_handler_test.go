@@ -0,0 +1,140 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"meilisearch/internal/auth"
+)
+
+func TestIncludeInactiveRequiresEditor(t *testing.T) {
+	h := &ProductHandler{}
+	tests := []struct {
+		name    string
+		role    auth.Role
+		handler http.HandlerFunc
+		request *http.Request
+	}{
+		{
+			name:    "search",
+			role:    auth.RoleReader,
+			handler: h.SearchProducts,
+			request: httptest.NewRequest(http.MethodGet, "/api/products/search?q=hinge&include_inactive=true", nil),
+		},
+		{
+			name:    "multi-search",
+			role:    auth.RoleReader,
+			handler: h.MultiSearchProducts,
+			request: httptest.NewRequest(http.MethodPost, "/api/products/multi-search",
+				strings.NewReader(`{"queries": [{"q": "hinge"}, {"q": "ply", "include_inactive": true}]}`)),
+		},
+		{
+			name:    "anonymous search",
+			role:    auth.RoleNone,
+			handler: h.SearchProducts,
+			request: httptest.NewRequest(http.MethodGet, "/api/products/search?q=hinge&include_inactive=1", nil),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := tt.request
+			if tt.role != auth.RoleNone {
+				r = r.WithContext(auth.WithPrincipal(r.Context(), &auth.Principal{Subject: "storefront", Role: tt.role, Method: auth.MethodAPIKey}))
+			}
+			w := httptest.NewRecorder()
+			tt.handler(w, r)
+
+			if w.Code != http.StatusForbidden {
+				t.Errorf("status = %d, want %d", w.Code, http.StatusForbidden)
+			}
+		})
+	}
+}
+
+func TestAllowsInactive(t *testing.T) {
+	tests := []struct {
+		principal *auth.Principal
+		want      bool
+	}{
+		{nil, false},
+		{&auth.Principal{Role: auth.RoleReader}, false},
+		{&auth.Principal{Role: auth.RoleEditor}, true},
+		{&auth.Principal{Role: auth.RoleAdmin}, true},
+	}
+
+	for _, tt := range tests {
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		if tt.principal != nil {
+			r = r.WithContext(auth.WithPrincipal(r.Context(), tt.principal))
+		}
+		if got := allowsInactive(r); got != tt.want {
+			t.Errorf("allowsInactive(%v) = %v, want %v", tt.principal, got, tt.want)
+		}
+	}
+}
+
+func TestJSONBodyLimit(t *testing.T) {
+	products := &ProductHandler{}
+	quotes := &QuoteHandler{}
+	synonyms := &SynonymHandler{}
+	tokens := &SearchTokenHandler{}
+	oversized := `{"queries": [{"q": "` + strings.Repeat("a", maxJSONBodyBytes) + `"}]}`
+	tests := []struct {
+		name    string
+		handler http.HandlerFunc
+		body    string
+		want    int
+	}{
+		{
+			name:    "oversized multi-search",
+			handler: products.MultiSearchProducts,
+			body:    oversized,
+			want:    http.StatusRequestEntityTooLarge,
+		},
+		{
+			name:    "malformed multi-search",
+			handler: products.MultiSearchProducts,
+			body:    `{"queries": [`,
+			want:    http.StatusBadRequest,
+		},
+		{
+			name:    "oversized match list",
+			handler: products.MatchList,
+			body:    `{"text": "` + strings.Repeat(`18mm ply 8x4\n`, maxJSONBodyBytes/12) + `"}`,
+			want:    http.StatusRequestEntityTooLarge,
+		},
+		{
+			name:    "oversized quote",
+			handler: quotes.CreateQuote,
+			body:    `{"customer": "` + strings.Repeat("a", maxJSONBodyBytes) + `"}`,
+			want:    http.StatusRequestEntityTooLarge,
+		},
+		{
+			name:    "oversized synonym group",
+			handler: synonyms.AddSynonymGroup,
+			body:    `{"terms": ["` + strings.Repeat("a", maxJSONBodyBytes) + `"]}`,
+			want:    http.StatusRequestEntityTooLarge,
+		},
+		{
+			name:    "oversized search token request",
+			handler: tokens.CreateSearchToken,
+			body:    `{"filter": "` + strings.Repeat("a", maxJSONBodyBytes) + `"}`,
+			want:    http.StatusRequestEntityTooLarge,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(tt.body))
+			w := httptest.NewRecorder()
+			tt.handler(w, r)
+
+			if w.Code != tt.want {
+				t.Errorf("status = %d, want %d", w.Code, tt.want)
+			}
+		})
+	}
+}
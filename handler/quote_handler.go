@@ -0,0 +1,134 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"meilisearch/dto"
+	"meilisearch/service"
+)
+
+// QuoteHandler handles HTTP requests for quotes
+type QuoteHandler struct {
+	service *service.QuoteService
+}
+
+// NewQuoteHandler creates a new quote handler
+func NewQuoteHandler(quoteService *service.QuoteService) *QuoteHandler {
+	return &QuoteHandler{
+		service: quoteService,
+	}
+}
+
+// CreateQuote handles requests to build and save a priced quote
+func (h *QuoteHandler) CreateQuote(w http.ResponseWriter, r *http.Request) {
+	var req dto.QuoteCreateRequest
+	if err := decodeJSONBody(w, r, &req); err != nil {
+		writeBodyError(w, err, "Invalid quote request body")
+		return
+	}
+
+	quote, err := h.service.Create(r.Context(), req)
+	if err != nil {
+		writeQuoteError(w, err)
+		return
+	}
+
+	message := "Quote created successfully"
+	if !quote.Totals.Complete {
+		message = "Quote created; some lines have no price"
+	}
+	response := dto.NewSuccessResponse(message, quote)
+	writeJSONResponse(w, http.StatusCreated, response)
+}
+
+// ListQuotes handles requests to list saved quotes
+func (h *QuoteHandler) ListQuotes(w http.ResponseWriter, r *http.Request) {
+	quotes, err := h.service.List()
+	if err != nil {
+		writeQuoteError(w, err)
+		return
+	}
+
+	response := dto.NewSuccessResponse("Quotes retrieved successfully", quotes)
+	writeJSONResponse(w, http.StatusOK, response)
+}
+
+// GetQuote handles requests to get a saved quote
+func (h *QuoteHandler) GetQuote(w http.ResponseWriter, r *http.Request) {
+	quote, err := h.service.Get(r.PathValue("id"))
+	if err != nil {
+		writeQuoteError(w, err)
+		return
+	}
+
+	response := dto.NewSuccessResponse("Quote retrieved successfully", quote)
+	writeJSONResponse(w, http.StatusOK, response)
+}
+
+// ExportQuote handles requests to download a quote as JSON or CSV
+func (h *QuoteHandler) ExportQuote(w http.ResponseWriter, r *http.Request) {
+	format := strings.ToLower(r.URL.Query().Get("format"))
+	if format == "" {
+		format = "json"
+	}
+	if format != "json" && format != "csv" {
+		response := dto.NewErrorResponse("BAD_REQUEST", "Parameter 'format' must be json or csv", "INVALID_FORMAT")
+		writeJSONResponse(w, http.StatusBadRequest, response)
+		return
+	}
+
+	quote, err := h.service.Get(r.PathValue("id"))
+	if err != nil {
+		writeQuoteError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Disposition", `attachment; filename="`+quote.ID+"."+format+`"`)
+	if format == "csv" {
+		w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+		w.WriteHeader(http.StatusOK)
+		if err := service.WriteQuoteCSV(w, quote); err != nil {
+			slog.ErrorContext(r.Context(), "failed to write quote CSV", "quote_id", quote.ID, "error", err)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(quote); err != nil {
+		slog.ErrorContext(r.Context(), "failed to write quote JSON", "quote_id", quote.ID, "error", err)
+	}
+}
+
+// DeleteQuote handles requests to delete a saved quote
+func (h *QuoteHandler) DeleteQuote(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	if err := h.service.Delete(id); err != nil {
+		writeQuoteError(w, err)
+		return
+	}
+
+	response := dto.NewSuccessResponse("Quote deleted successfully", map[string]string{"id": id})
+	writeJSONResponse(w, http.StatusOK, response)
+}
+
+// writeQuoteError maps quote service errors to HTTP responses
+func writeQuoteError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, service.ErrQuoteNotFound):
+		response := dto.NewErrorResponse("NOT_FOUND", "Quote not found", "QUOTE_NOT_FOUND")
+		writeJSONResponse(w, http.StatusNotFound, response)
+	case errors.Is(err, service.ErrInvalidQuote):
+		response := dto.NewErrorResponse("BAD_REQUEST", err.Error(), "INVALID_QUOTE")
+		writeJSONResponse(w, http.StatusBadRequest, response)
+	default:
+		response := dto.NewErrorResponse("QUOTE_OPERATION_FAILED", "Quote operation failed", "INTERNAL_ERROR")
+		writeJSONResponse(w, http.StatusInternalServerError, response)
+	}
+}
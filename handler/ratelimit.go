@@ -0,0 +1,54 @@
+package handler
+
+import (
+	"fmt"
+	"log/slog"
+	"math"
+	"net/http"
+	"strconv"
+
+	"meilisearch/dto"
+	"meilisearch/internal/metrics"
+	"meilisearch/internal/ratelimit"
+)
+
+// Rate limit response headers
+const (
+	RateLimitLimitHeader     = "X-RateLimit-Limit"
+	RateLimitRemainingHeader = "X-RateLimit-Remaining"
+	RateLimitResetHeader     = "X-RateLimit-Reset"
+)
+
+// limitRate takes a token from the bucket of a client, such as "ip:10.0.0.1"
+// or "api_key:storefront", for the matched route and reports the bucket in
+// the X-RateLimit-* headers. Over the limit it answers 429 with Retry-After
+// and returns false. A failing store lets the request through.
+func limitRate(limiter *ratelimit.Limiter, w http.ResponseWriter, r *http.Request, client string) bool {
+	result, limited, err := limiter.Take(r.Context(), r.Pattern, client)
+	if err != nil {
+		slog.WarnContext(r.Context(), "rate limit store failed, allowing request", "error", err)
+		return true
+	}
+	if !limited {
+		return true
+	}
+
+	w.Header().Set(RateLimitLimitHeader, strconv.Itoa(result.Limit))
+	w.Header().Set(RateLimitRemainingHeader, strconv.Itoa(result.Remaining))
+	w.Header().Set(RateLimitResetHeader, strconv.Itoa(ceilSeconds(result.Reset.Seconds())))
+	if result.Allowed {
+		return true
+	}
+
+	retryAfter := ceilSeconds(result.RetryAfter.Seconds())
+	w.Header().Set("Retry-After", strconv.Itoa(retryAfter))
+	metrics.RateLimited.WithLabelValues(routeLabel(r)).Inc()
+	message := fmt.Sprintf("Rate limit exceeded, retry in %d seconds", retryAfter)
+	writeJSONResponse(w, http.StatusTooManyRequests, dto.NewErrorResponse("TOO_MANY_REQUESTS", message, "RATE_LIMITED"))
+	return false
+}
+
+// ceilSeconds rounds a number of seconds up to a whole second, at least one
+func ceilSeconds(value float64) int {
+	return max(1, int(math.Ceil(value)))
+}
@@ -0,0 +1,79 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"meilisearch/internal/auth"
+	"meilisearch/internal/ratelimit"
+)
+
+func TestRequireRateLimits(t *testing.T) {
+	secured := func() *auth.Authenticator {
+		return auth.NewAuthenticator(
+			auth.WithAPIKey("storefront", auth.RoleReader, "reader-key"),
+			auth.WithAPIKey("kiosk", auth.RoleReader, "other-key"),
+		)
+	}
+	tests := []struct {
+		name          string
+		authenticator *auth.Authenticator
+		keys          []string
+		codes         []int
+	}{
+		{
+			name:          "failed authentication counts against the client IP",
+			authenticator: secured(),
+			keys:          []string{"guess", "guess", "guess"},
+			codes:         []int{http.StatusUnauthorized, http.StatusUnauthorized, http.StatusTooManyRequests},
+		},
+		{
+			name:          "anonymous callers count against the client IP",
+			authenticator: auth.NewAuthenticator(),
+			keys:          []string{"", "", ""},
+			codes:         []int{http.StatusOK, http.StatusOK, http.StatusTooManyRequests},
+		},
+		{
+			name:          "each key has its own bucket",
+			authenticator: secured(),
+			keys:          []string{"reader-key", "reader-key", "other-key", "other-key", "reader-key"},
+			codes:         []int{http.StatusOK, http.StatusOK, http.StatusOK, http.StatusOK, http.StatusTooManyRequests},
+		},
+		{
+			name:          "keys are not limited by the client IP",
+			authenticator: secured(),
+			keys:          []string{"guess", "guess", "guess", "reader-key", "reader-key"},
+			codes:         []int{http.StatusUnauthorized, http.StatusUnauthorized, http.StatusTooManyRequests, http.StatusOK, http.StatusOK},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			guard := &authorizer{
+				authenticator: tt.authenticator,
+				limiter:       ratelimit.NewLimiter(ratelimit.WithDefaultLimit(ratelimit.Limit{Rate: 0.01, Burst: 2})),
+			}
+			mux := http.NewServeMux()
+			mux.Handle("GET /api/test", guard.require(auth.RoleReader, func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			}))
+
+			for i, key := range tt.keys {
+				r := httptest.NewRequest(http.MethodGet, "/api/test", nil)
+				if key != "" {
+					r.Header.Set(auth.APIKeyHeader, key)
+				}
+				w := httptest.NewRecorder()
+				mux.ServeHTTP(w, r)
+
+				if w.Code != tt.codes[i] {
+					t.Errorf("request %d status = %d, want %d", i+1, w.Code, tt.codes[i])
+				}
+				if w.Code == http.StatusTooManyRequests && w.Header().Get("Retry-After") == "" {
+					t.Errorf("request %d: 429 without Retry-After", i+1)
+				}
+			}
+		})
+	}
+}
@@ -2,22 +2,78 @@ package handler
 
 import (
 	"net/http"
+
+	"meilisearch/internal/auth"
+	"meilisearch/internal/ratelimit"
+	"meilisearch/service"
+
+	"github.com/meilisearch/meilisearch-go"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 )
 
-// SetupRoutes configures all the HTTP routes for the application
-func SetupRoutes(client interface{}) *http.ServeMux {
+// SetupRoutes configures all the HTTP routes for the application. The
+// readiness probe combines the given readiness flag with the health service's
+// Meilisearch checks. API routes require the role they are registered with
+// and are rate limited per caller; the health, metrics and root routes are
+// public and unlimited.
+func SetupRoutes(client meilisearch.ServiceManager, readiness *Readiness, healthService *service.HealthService, tokenService *service.SearchTokenService, authenticator *auth.Authenticator, limiter *ratelimit.Limiter, productOptions ...service.ProductOption) *http.ServeMux {
 	mux := http.NewServeMux()
+	guard := &authorizer{authenticator: authenticator, limiter: limiter}
 
 	// Create handlers
-	productHandler := NewProductHandler(client)
+	productService := service.NewProductService(client, productOptions...)
+	productHandler := NewProductHandler(productService)
+	brandHandler := NewBrandHandler(service.NewBrandService(client, service.DefaultBrandsFile))
+	categoryHandler := NewCategoryHandler(service.NewCategoryService(client, service.DefaultCategoriesFile), productService)
+	quoteHandler := NewQuoteHandler(service.NewQuoteService(client, service.DefaultQuotesDir))
+	synonymHandler := NewSynonymHandler(service.NewSynonymService(client, service.DefaultSynonymsFile))
+	healthHandler := NewHealthHandler(readiness, healthService)
+	searchTokenHandler := NewSearchTokenHandler(tokenService)
+
+	// Product routes; removing and restoring products needs an editor
+	mux.Handle("GET /api/products/search", guard.require(auth.RoleReader, productHandler.SearchProducts))
+	mux.Handle("POST /api/products/multi-search", guard.require(auth.RoleReader, productHandler.MultiSearchProducts))
+	mux.Handle("POST /api/products/match-list", guard.require(auth.RoleReader, productHandler.MatchList))
+	mux.Handle("GET /api/products/suggest", guard.require(auth.RoleReader, productHandler.SuggestProducts))
+	mux.Handle("GET /api/products/stats", guard.require(auth.RoleReader, productHandler.GetIndexStats))
+	mux.Handle("GET /api/products/compare", guard.require(auth.RoleReader, productHandler.CompareProducts))
+	mux.Handle("GET /api/products/", guard.require(auth.RoleReader, productHandler.GetProductByID))
+	mux.Handle("GET /api/products/{id}", guard.require(auth.RoleReader, productHandler.GetProductByID))
+	mux.Handle("GET /api/products/{id}/variants", guard.require(auth.RoleReader, productHandler.GetProductVariants))
+	mux.Handle("GET /api/products/{id}/similar", guard.require(auth.RoleReader, productHandler.GetSimilarProducts))
+	mux.Handle("DELETE /api/products/{id}", guard.require(auth.RoleEditor, productHandler.DeleteProduct))
+	mux.Handle("POST /api/products/{id}/restore", guard.require(auth.RoleEditor, productHandler.RestoreProduct))
+
+	// Brand routes
+	mux.Handle("GET /api/brands", guard.require(auth.RoleReader, brandHandler.ListBrands))
+
+	// Category routes
+	mux.Handle("GET /api/categories", guard.require(auth.RoleReader, categoryHandler.ListCategories))
+	mux.Handle("GET /api/categories/{path...}", guard.require(auth.RoleReader, categoryHandler.BrowseCategory))
 
-	// Product routes
-	mux.HandleFunc("/api/products/search", productHandler.SearchProducts)
-	mux.HandleFunc("/api/products/", productHandler.GetProductByID)
-	mux.HandleFunc("/api/products/stats", productHandler.GetIndexStats)
+	// Quote routes; quotes carry customer pricing and are managed by editors
+	mux.Handle("GET /api/quotes", guard.require(auth.RoleEditor, quoteHandler.ListQuotes))
+	mux.Handle("POST /api/quotes", guard.require(auth.RoleEditor, quoteHandler.CreateQuote))
+	mux.Handle("GET /api/quotes/{id}", guard.require(auth.RoleEditor, quoteHandler.GetQuote))
+	mux.Handle("GET /api/quotes/{id}/export", guard.require(auth.RoleEditor, quoteHandler.ExportQuote))
+	mux.Handle("DELETE /api/quotes/{id}", guard.require(auth.RoleEditor, quoteHandler.DeleteQuote))
 
-	// Health check
-	mux.HandleFunc("/health", productHandler.HealthCheck)
+	// Synonym admin routes
+	mux.Handle("GET /api/synonyms", guard.require(auth.RoleAdmin, synonymHandler.ListSynonyms))
+	mux.Handle("POST /api/synonyms", guard.require(auth.RoleAdmin, synonymHandler.AddSynonymGroup))
+	mux.Handle("DELETE /api/synonyms/{id}", guard.require(auth.RoleAdmin, synonymHandler.RemoveSynonymGroup))
+
+	// Tenant tokens for searching Meilisearch directly from browsers
+	mux.Handle("POST /api/auth/search-token", guard.require(auth.RoleReader, searchTokenHandler.CreateSearchToken))
+
+	// Prometheus metrics; route labels and counts are operational details,
+	// so scrapers authenticate as admins
+	mux.Handle("GET /metrics", guard.require(auth.RoleAdmin, promhttp.Handler().ServeHTTP))
+
+	// Health checks; /health is kept as an alias of the readiness probe
+	mux.HandleFunc("GET /health/live", healthHandler.Live)
+	mux.HandleFunc("GET /health/ready", healthHandler.Ready)
+	mux.HandleFunc("/health", healthHandler.Ready)
 
 	// Root endpoint
 	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
@@ -28,40 +84,29 @@ func SetupRoutes(client interface{}) *http.ServeMux {
 			"version": "1.0.0",
 			"endpoints": {
 				"search": "/api/products/search?q=<query>",
-				"product": "/api/products?id=<id>",
+				"multi_search": "POST /api/products/multi-search",
+				"match_list": "POST /api/products/match-list",
+				"suggest": "/api/products/suggest?q=<prefix>",
+				"product": "/api/products/<id>",
+				"variants": "/api/products/<id>/variants",
+				"similar": "/api/products/<id>/similar",
+				"compare": "/api/products/compare?ids=<id>,<id>",
+				"delete": "DELETE /api/products/<id>",
+				"restore": "POST /api/products/<id>/restore",
 				"stats": "/api/products/stats",
-				"health": "/health"
+				"brands": "/api/brands",
+				"categories": "/api/categories",
+				"browse": "/api/categories/<lvl0>/<lvl1>/...",
+				"quotes": "/api/quotes",
+				"synonyms": "/api/synonyms",
+				"search_token": "POST /api/auth/search-token",
+				"metrics": "/metrics",
+				"health": "/health",
+				"liveness": "/health/live",
+				"readiness": "/health/ready"
 			}
 		}`))
 	})
 
 	return mux
 }
-
-// CORSMiddleware adds CORS headers to responses
-func CORSMiddleware(next http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		// Set CORS headers
-		w.Header().Set("Access-Control-Allow-Origin", "*")
-		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
-
-		// Handle preflight requests
-		if r.Method == "OPTIONS" {
-			w.WriteHeader(http.StatusOK)
-			return
-		}
-
-		next.ServeHTTP(w, r)
-	})
-}
-
-// LoggingMiddleware logs HTTP requests
-func LoggingMiddleware(next http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		// Log the request
-		println("Request:", r.Method, r.URL.Path, r.RemoteAddr)
-
-		next.ServeHTTP(w, r)
-	})
-}
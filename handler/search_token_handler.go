@@ -0,0 +1,66 @@
+package handler
+
+import (
+	"errors"
+	"io"
+	"net/http"
+
+	"meilisearch/dto"
+	"meilisearch/internal/auth"
+	"meilisearch/service"
+)
+
+// SearchTokenHandler handles HTTP requests for Meilisearch tenant tokens
+type SearchTokenHandler struct {
+	service *service.SearchTokenService
+}
+
+// NewSearchTokenHandler creates a new search token handler
+func NewSearchTokenHandler(tokenService *service.SearchTokenService) *SearchTokenHandler {
+	return &SearchTokenHandler{
+		service: tokenService,
+	}
+}
+
+// CreateSearchToken handles requests to mint a tenant token. Callers whose
+// token carries a categories claim only get tokens within those categories.
+func (h *SearchTokenHandler) CreateSearchToken(w http.ResponseWriter, r *http.Request) {
+	// An empty body asks for an unrestricted token with the default lifetime
+	var req dto.SearchTokenRequest
+	if err := decodeJSONBody(w, r, &req); err != nil && !errors.Is(err, io.EOF) {
+		writeBodyError(w, err, "Invalid search token request body")
+		return
+	}
+
+	var allowed []string
+	if principal := auth.PrincipalFromContext(r.Context()); principal != nil && principal.Claims != nil {
+		allowed = principal.Claims.Categories
+	}
+
+	token, err := h.service.Create(r.Context(), req, allowed)
+	if err != nil {
+		writeSearchTokenError(w, err)
+		return
+	}
+
+	response := dto.NewSuccessResponse("Search token created successfully", token)
+	writeJSONResponse(w, http.StatusCreated, response)
+}
+
+// writeSearchTokenError maps search token errors to HTTP responses
+func writeSearchTokenError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, service.ErrSearchTokensDisabled):
+		response := dto.NewErrorResponse("SERVICE_UNAVAILABLE", "Search tokens are not configured on this server", "SEARCH_TOKENS_DISABLED")
+		writeJSONResponse(w, http.StatusServiceUnavailable, response)
+	case errors.Is(err, service.ErrInvalidSearchToken):
+		response := dto.NewErrorResponse("BAD_REQUEST", err.Error(), "INVALID_SEARCH_TOKEN_REQUEST")
+		writeJSONResponse(w, http.StatusBadRequest, response)
+	case errors.Is(err, service.ErrRestrictionNotAllowed):
+		response := dto.NewErrorResponse("FORBIDDEN", err.Error(), "CATEGORY_NOT_ALLOWED")
+		writeJSONResponse(w, http.StatusForbidden, response)
+	default:
+		response := dto.NewErrorResponse("SEARCH_TOKEN_FAILED", "Failed to create search token", "INTERNAL_ERROR")
+		writeJSONResponse(w, http.StatusInternalServerError, response)
+	}
+}
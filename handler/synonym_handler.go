@@ -0,0 +1,82 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"meilisearch/dto"
+	"meilisearch/service"
+)
+
+// SynonymHandler handles HTTP requests for managing search synonyms
+type SynonymHandler struct {
+	service *service.SynonymService
+}
+
+// NewSynonymHandler creates a new synonym handler
+func NewSynonymHandler(synonymService *service.SynonymService) *SynonymHandler {
+	return &SynonymHandler{
+		service: synonymService,
+	}
+}
+
+// ListSynonyms handles requests to list synonym groups
+func (h *SynonymHandler) ListSynonyms(w http.ResponseWriter, r *http.Request) {
+	set, err := h.service.List()
+	if err != nil {
+		response := dto.NewErrorResponse("SYNONYMS_FAILED", "Failed to load synonyms", "INTERNAL_ERROR")
+		writeJSONResponse(w, http.StatusInternalServerError, response)
+		return
+	}
+
+	response := dto.NewSuccessResponse("Synonyms retrieved successfully", set)
+	writeJSONResponse(w, http.StatusOK, response)
+}
+
+// AddSynonymGroup handles requests to add a synonym group
+func (h *SynonymHandler) AddSynonymGroup(w http.ResponseWriter, r *http.Request) {
+	var group dto.SynonymGroup
+	if err := decodeJSONBody(w, r, &group); err != nil {
+		writeBodyError(w, err, "Invalid synonym group body")
+		return
+	}
+
+	result, err := h.service.Add(r.Context(), group)
+	if err != nil {
+		writeSynonymError(w, err)
+		return
+	}
+
+	response := dto.NewSuccessResponse("Synonym group added successfully", result)
+	writeJSONResponse(w, http.StatusAccepted, response)
+}
+
+// RemoveSynonymGroup handles requests to remove a synonym group
+func (h *SynonymHandler) RemoveSynonymGroup(w http.ResponseWriter, r *http.Request) {
+	result, err := h.service.Remove(r.Context(), r.PathValue("id"))
+	if err != nil {
+		writeSynonymError(w, err)
+		return
+	}
+
+	response := dto.NewSuccessResponse("Synonym group removed successfully", result)
+	writeJSONResponse(w, http.StatusAccepted, response)
+}
+
+// writeSynonymError maps synonym service errors to HTTP responses
+func writeSynonymError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, service.ErrInvalidSynonymGroup):
+		response := dto.NewErrorResponse("BAD_REQUEST", err.Error(), "INVALID_SYNONYM_GROUP")
+		writeJSONResponse(w, http.StatusBadRequest, response)
+	case errors.Is(err, service.ErrSynonymGroupExists):
+		response := dto.NewErrorResponse("CONFLICT", err.Error(), "SYNONYM_GROUP_EXISTS")
+		writeJSONResponse(w, http.StatusConflict, response)
+	case errors.Is(err, service.ErrSynonymGroupNotFound):
+		response := dto.NewErrorResponse("NOT_FOUND", err.Error(), "SYNONYM_GROUP_NOT_FOUND")
+		writeJSONResponse(w, http.StatusNotFound, response)
+	default:
+		response := dto.NewErrorResponse("SYNONYMS_FAILED", "Failed to update synonyms", "INTERNAL_ERROR")
+		writeJSONResponse(w, http.StatusInternalServerError, response)
+	}
+}
@@ -0,0 +1,248 @@
+// Package auth authenticates API callers by static API key or JWT bearer
+// token and describes what their role allows.
+package auth
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+// APIKeyHeader carries a static API key
+const APIKeyHeader = "X-API-Key"
+
+// Role is the access level of a caller. Each role includes the permissions of
+// the roles below it.
+type Role int
+
+// Roles, from least to most privileged
+const (
+	RoleNone Role = iota
+	// RoleReader may search and read the catalog
+	RoleReader
+	// RoleEditor may also manage quotes and soft-delete or restore products
+	RoleEditor
+	// RoleAdmin may also change the search configuration
+	RoleAdmin
+)
+
+// roleNames maps roles to their names in configuration and tokens
+var roleNames = map[Role]string{
+	RoleReader: "reader",
+	RoleEditor: "editor",
+	RoleAdmin:  "admin",
+}
+
+// String returns the role name
+func (r Role) String() string {
+	if name, ok := roleNames[r]; ok {
+		return name
+	}
+	return "none"
+}
+
+// Allows reports whether the role grants the permissions of required
+func (r Role) Allows(required Role) bool {
+	return r >= required
+}
+
+// ParseRole parses a role name such as "editor"
+func ParseRole(name string) (Role, error) {
+	for role, roleName := range roleNames {
+		if strings.EqualFold(name, roleName) {
+			return role, nil
+		}
+	}
+	return RoleNone, fmt.Errorf("unknown role %q: must be reader, editor or admin", name)
+}
+
+// Authentication methods of a Principal
+const (
+	MethodAPIKey = "api_key"
+	MethodJWT    = "jwt"
+	// MethodNone marks anonymous callers
+	MethodNone = "none"
+)
+
+// Principal is an authenticated caller
+type Principal struct {
+	// Subject names the caller: the API key name or the token subject
+	Subject string
+	Role    Role
+	Method  string
+	// Claims holds the JWT claims; nil for API keys
+	Claims *Claims
+}
+
+var (
+	// ErrMissingCredentials is returned when a request carries neither an
+	// API key nor a bearer token
+	ErrMissingCredentials = errors.New("missing credentials")
+	// ErrInvalidCredentials is returned for an unknown API key or an invalid
+	// or expired token
+	ErrInvalidCredentials = errors.New("invalid credentials")
+)
+
+// Claims are the JWT claims read from bearer tokens. The role claim holds a
+// role name; categories, when set, limits a dealer to those categories.
+type Claims struct {
+	Role       string   `json:"role"`
+	Categories []string `json:"categories,omitempty"`
+	jwt.RegisteredClaims
+}
+
+// apiKey is a configured static API key
+type apiKey struct {
+	name string
+	role Role
+}
+
+// Authenticator checks the credentials of requests
+type Authenticator struct {
+	// keys maps the SHA-256 of each API key to its owner, so lookups do not
+	// compare secrets byte by byte
+	keys      map[[sha256.Size]byte]apiKey
+	jwtSecret []byte
+	issuer    string
+	audience  string
+	disabled  bool
+}
+
+// Option configures an Authenticator
+type Option func(*Authenticator)
+
+// WithAPIKey accepts a static API key, sent in the X-API-Key header or as a
+// bearer token, for the named caller and role
+func WithAPIKey(name string, role Role, key string) Option {
+	return func(a *Authenticator) {
+		a.keys[sha256.Sum256([]byte(key))] = apiKey{name: name, role: role}
+	}
+}
+
+// WithJWT accepts HS256 bearer tokens signed with secret. The issuer and
+// audience are checked when not empty.
+func WithJWT(secret, issuer, audience string) Option {
+	return func(a *Authenticator) {
+		a.jwtSecret = []byte(secret)
+		a.issuer = issuer
+		a.audience = audience
+	}
+}
+
+// WithDisabled turns authentication off: every caller is treated as an
+// admin. It is meant for local development only.
+func WithDisabled() Option {
+	return func(a *Authenticator) {
+		a.disabled = true
+	}
+}
+
+// NewAuthenticator creates an authenticator for the given credentials
+func NewAuthenticator(opts ...Option) *Authenticator {
+	a := &Authenticator{keys: make(map[[sha256.Size]byte]apiKey)}
+	for _, opt := range opts {
+		opt(a)
+	}
+	return a
+}
+
+// Enabled reports whether any credentials are configured. Without them only
+// anonymous read access is possible.
+func (a *Authenticator) Enabled() bool {
+	return len(a.keys) > 0 || len(a.jwtSecret) > 0
+}
+
+// Disabled reports whether authentication was explicitly turned off
+func (a *Authenticator) Disabled() bool {
+	return a.disabled
+}
+
+// Authenticate identifies the caller of a request from its X-API-Key header
+// or its Authorization bearer token. A bearer token matching an API key is
+// accepted as that key; any other is verified as a JWT.
+func (a *Authenticator) Authenticate(r *http.Request) (*Principal, error) {
+	if key := r.Header.Get(APIKeyHeader); key != "" {
+		if principal, ok := a.lookupKey(key); ok {
+			return principal, nil
+		}
+		return nil, ErrInvalidCredentials
+	}
+
+	header := r.Header.Get("Authorization")
+	if header == "" {
+		return nil, ErrMissingCredentials
+	}
+	scheme, token, found := strings.Cut(header, " ")
+	if !found || !strings.EqualFold(scheme, "Bearer") || strings.TrimSpace(token) == "" {
+		return nil, fmt.Errorf("%w: expected a Bearer token", ErrInvalidCredentials)
+	}
+	token = strings.TrimSpace(token)
+	if principal, ok := a.lookupKey(token); ok {
+		return principal, nil
+	}
+	return a.parseJWT(token)
+}
+
+// lookupKey finds the owner of a static API key
+func (a *Authenticator) lookupKey(key string) (*Principal, bool) {
+	owner, ok := a.keys[sha256.Sum256([]byte(key))]
+	if !ok {
+		return nil, false
+	}
+	return &Principal{Subject: owner.name, Role: owner.role, Method: MethodAPIKey}, true
+}
+
+// parseJWT verifies a bearer token and reads its subject and role
+func (a *Authenticator) parseJWT(token string) (*Principal, error) {
+	if len(a.jwtSecret) == 0 {
+		return nil, ErrInvalidCredentials
+	}
+
+	claims := &Claims{}
+	parser := jwt.NewParser(jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}))
+	if _, err := parser.ParseWithClaims(token, claims, func(*jwt.Token) (interface{}, error) {
+		return a.jwtSecret, nil
+	}); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidCredentials, err)
+	}
+	// jwt skips the expiry check when exp is absent, and tokens without a
+	// subject cannot be told apart in logs and rate limits
+	if !claims.VerifyExpiresAt(time.Now(), true) {
+		return nil, fmt.Errorf("%w: token has no expiry", ErrInvalidCredentials)
+	}
+	if claims.Subject == "" {
+		return nil, fmt.Errorf("%w: token has no subject", ErrInvalidCredentials)
+	}
+	if a.issuer != "" && !claims.VerifyIssuer(a.issuer, true) {
+		return nil, fmt.Errorf("%w: unexpected issuer", ErrInvalidCredentials)
+	}
+	if a.audience != "" && !claims.VerifyAudience(a.audience, true) {
+		return nil, fmt.Errorf("%w: unexpected audience", ErrInvalidCredentials)
+	}
+	role, err := ParseRole(claims.Role)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidCredentials, err)
+	}
+	return &Principal{Subject: claims.Subject, Role: role, Method: MethodJWT, Claims: claims}, nil
+}
+
+// principalKey is the context key of the authenticated caller
+type principalKey struct{}
+
+// WithPrincipal returns a context carrying the authenticated caller
+func WithPrincipal(ctx context.Context, principal *Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, principal)
+}
+
+// PrincipalFromContext returns the authenticated caller, or nil for an
+// anonymous request
+func PrincipalFromContext(ctx context.Context) *Principal {
+	principal, _ := ctx.Value(principalKey{}).(*Principal)
+	return principal
+}
@@ -0,0 +1,239 @@
+package auth
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+func TestRoleAllows(t *testing.T) {
+	tests := []struct {
+		role     Role
+		required Role
+		want     bool
+	}{
+		{RoleNone, RoleNone, true},
+		{RoleNone, RoleReader, false},
+		{RoleReader, RoleReader, true},
+		{RoleReader, RoleEditor, false},
+		{RoleEditor, RoleReader, true},
+		{RoleEditor, RoleEditor, true},
+		{RoleEditor, RoleAdmin, false},
+		{RoleAdmin, RoleReader, true},
+		{RoleAdmin, RoleEditor, true},
+		{RoleAdmin, RoleAdmin, true},
+	}
+
+	for _, tt := range tests {
+		if got := tt.role.Allows(tt.required); got != tt.want {
+			t.Errorf("%s.Allows(%s) = %v, want %v", tt.role, tt.required, got, tt.want)
+		}
+	}
+}
+
+func TestParseRole(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    Role
+		wantErr bool
+	}{
+		{"reader", RoleReader, false},
+		{"Editor", RoleEditor, false},
+		{"ADMIN", RoleAdmin, false},
+		{"none", RoleNone, true},
+		{"", RoleNone, true},
+		{"root", RoleNone, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseRole(tt.name)
+			if got != tt.want || (err != nil) != tt.wantErr {
+				t.Errorf("ParseRole(%q) = %v, %v; want %v, error %v", tt.name, got, err, tt.want, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestAuthenticate(t *testing.T) {
+	const secret = "test-secret"
+	authenticator := NewAuthenticator(
+		WithAPIKey("storefront", RoleReader, "reader-key"),
+		WithAPIKey("ops", RoleAdmin, "admin-key"),
+		WithJWT(secret, "catalog-auth", "catalog-api"),
+	)
+	expiry := jwt.NewNumericDate(time.Now().Add(time.Hour))
+	valid := jwt.RegisteredClaims{Subject: "dealer-7", Issuer: "catalog-auth", Audience: jwt.ClaimStrings{"catalog-api"}, ExpiresAt: expiry}
+
+	tests := []struct {
+		name    string
+		header  string
+		value   string
+		subject string
+		role    Role
+		method  string
+		err     error
+	}{
+		{
+			name: "no credentials",
+			err:  ErrMissingCredentials,
+		},
+		{
+			name:    "API key header",
+			header:  APIKeyHeader,
+			value:   "reader-key",
+			subject: "storefront",
+			role:    RoleReader,
+			method:  MethodAPIKey,
+		},
+		{
+			name:    "API key as bearer token",
+			header:  "Authorization",
+			value:   "Bearer admin-key",
+			subject: "ops",
+			role:    RoleAdmin,
+			method:  MethodAPIKey,
+		},
+		{
+			name:   "unknown API key",
+			header: APIKeyHeader,
+			value:  "guess",
+			err:    ErrInvalidCredentials,
+		},
+		{
+			name:   "basic scheme",
+			header: "Authorization",
+			value:  "Basic cmVhZGVyLWtleQ==",
+			err:    ErrInvalidCredentials,
+		},
+		{
+			name:   "empty bearer token",
+			header: "Authorization",
+			value:  "Bearer  ",
+			err:    ErrInvalidCredentials,
+		},
+		{
+			name:    "valid JWT",
+			header:  "Authorization",
+			value:   "Bearer " + signToken(t, secret, jwt.SigningMethodHS256, Claims{Role: "editor", RegisteredClaims: valid}),
+			subject: "dealer-7",
+			role:    RoleEditor,
+			method:  MethodJWT,
+		},
+		{
+			name:   "JWT signed with another secret",
+			header: "Authorization",
+			value:  "Bearer " + signToken(t, "other-secret", jwt.SigningMethodHS256, Claims{Role: "editor", RegisteredClaims: valid}),
+			err:    ErrInvalidCredentials,
+		},
+		{
+			name:   "JWT signed with another algorithm",
+			header: "Authorization",
+			value:  "Bearer " + signToken(t, secret, jwt.SigningMethodHS512, Claims{Role: "editor", RegisteredClaims: valid}),
+			err:    ErrInvalidCredentials,
+		},
+		{
+			name:   "expired JWT",
+			header: "Authorization",
+			value: "Bearer " + signToken(t, secret, jwt.SigningMethodHS256, Claims{Role: "reader", RegisteredClaims: withClaims(valid, func(c *jwt.RegisteredClaims) {
+				c.ExpiresAt = jwt.NewNumericDate(time.Now().Add(-time.Minute))
+			})}),
+			err: ErrInvalidCredentials,
+		},
+		{
+			name:   "JWT without expiry",
+			header: "Authorization",
+			value: "Bearer " + signToken(t, secret, jwt.SigningMethodHS256, Claims{Role: "reader", RegisteredClaims: withClaims(valid, func(c *jwt.RegisteredClaims) {
+				c.ExpiresAt = nil
+			})}),
+			err: ErrInvalidCredentials,
+		},
+		{
+			name:   "JWT without subject",
+			header: "Authorization",
+			value: "Bearer " + signToken(t, secret, jwt.SigningMethodHS256, Claims{Role: "reader", RegisteredClaims: withClaims(valid, func(c *jwt.RegisteredClaims) {
+				c.Subject = ""
+			})}),
+			err: ErrInvalidCredentials,
+		},
+		{
+			name:   "JWT from another issuer",
+			header: "Authorization",
+			value: "Bearer " + signToken(t, secret, jwt.SigningMethodHS256, Claims{Role: "reader", RegisteredClaims: withClaims(valid, func(c *jwt.RegisteredClaims) {
+				c.Issuer = "elsewhere"
+			})}),
+			err: ErrInvalidCredentials,
+		},
+		{
+			name:   "JWT for another audience",
+			header: "Authorization",
+			value: "Bearer " + signToken(t, secret, jwt.SigningMethodHS256, Claims{Role: "reader", RegisteredClaims: withClaims(valid, func(c *jwt.RegisteredClaims) {
+				c.Audience = jwt.ClaimStrings{"billing-api"}
+			})}),
+			err: ErrInvalidCredentials,
+		},
+		{
+			name:   "JWT with an unknown role",
+			header: "Authorization",
+			value:  "Bearer " + signToken(t, secret, jwt.SigningMethodHS256, Claims{Role: "root", RegisteredClaims: valid}),
+			err:    ErrInvalidCredentials,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/api/products", nil)
+			if tt.header != "" {
+				r.Header.Set(tt.header, tt.value)
+			}
+			principal, err := authenticator.Authenticate(r)
+			if tt.err != nil {
+				if !errors.Is(err, tt.err) {
+					t.Fatalf("Authenticate() error = %v, want %v", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Authenticate() error = %v", err)
+			}
+			if principal.Subject != tt.subject || principal.Role != tt.role || principal.Method != tt.method {
+				t.Errorf("Authenticate() = %s %s %s, want %s %s %s",
+					principal.Subject, principal.Role, principal.Method, tt.subject, tt.role, tt.method)
+			}
+		})
+	}
+}
+
+func TestAuthenticateWithoutJWTSecret(t *testing.T) {
+	authenticator := NewAuthenticator(WithAPIKey("storefront", RoleReader, "reader-key"))
+	token := signToken(t, "", jwt.SigningMethodHS256, Claims{Role: "admin", RegisteredClaims: jwt.RegisteredClaims{
+		Subject:   "dealer-7",
+		ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+	}})
+
+	r := httptest.NewRequest(http.MethodGet, "/api/products", nil)
+	r.Header.Set("Authorization", "Bearer "+token)
+	if _, err := authenticator.Authenticate(r); !errors.Is(err, ErrInvalidCredentials) {
+		t.Errorf("Authenticate() error = %v, want %v", err, ErrInvalidCredentials)
+	}
+}
+
+// signToken signs claims into a JWT
+func signToken(t *testing.T, secret string, method jwt.SigningMethod, claims Claims) string {
+	t.Helper()
+	token, err := jwt.NewWithClaims(method, claims).SignedString([]byte(secret))
+	if err != nil {
+		t.Fatalf("signing token: %v", err)
+	}
+	return token
+}
+
+// withClaims returns a copy of claims changed by edit
+func withClaims(claims jwt.RegisteredClaims, edit func(*jwt.RegisteredClaims)) jwt.RegisteredClaims {
+	edit(&claims)
+	return claims
+}
@@ -0,0 +1,538 @@
+// Package config holds the settings shared by the indexer and the API server
+// and builds the Meilisearch client from them.
+package config
+
+import (
+	"fmt"
+	"log/slog"
+	"math"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"meilisearch/internal/auth"
+	"meilisearch/internal/cors"
+	"meilisearch/internal/ratelimit"
+	"meilisearch/internal/telemetry"
+	"meilisearch/service"
+
+	"github.com/meilisearch/meilisearch-go"
+)
+
+// Defaults used when the corresponding environment variable is unset
+const (
+	DefaultMeilisearchURL = "http://localhost:7700"
+	DefaultPort           = "8080"
+	DefaultDataFile       = "sku.json"
+	DefaultBatchSize      = 1000
+	DefaultLogFormat      = LogFormatText
+	DefaultTraceExporter  = telemetry.ExporterNone
+	DefaultTraceFile      = "traces.json"
+	DefaultTraceSample    = 1.0
+
+	DefaultReadTimeout       = 15 * time.Second
+	DefaultReadHeaderTimeout = 5 * time.Second
+	DefaultWriteTimeout      = 30 * time.Second
+	DefaultIdleTimeout       = 60 * time.Second
+	DefaultShutdownDelay     = 5 * time.Second
+	DefaultShutdownTimeout   = 20 * time.Second
+)
+
+// Log output formats
+const (
+	LogFormatText = "text"
+	LogFormatJSON = "json"
+)
+
+// Config holds the settings read from the environment
+type Config struct {
+	// MeilisearchURL is the address of the Meilisearch server (MEILISEARCH_URL)
+	MeilisearchURL string
+	// MasterKey authenticates against Meilisearch (MASTER_KEY)
+	MasterKey string
+
+	// LogFormat is "text" or "json" (LOG_FORMAT)
+	LogFormat string
+	// LogLevel is the minimum level logged: debug, info, warn or error (LOG_LEVEL)
+	LogLevel slog.Level
+
+	// TraceExporter is where spans are sent: none, otlp, stdout or file
+	// (TRACE_EXPORTER). The OTLP endpoint is read from the standard
+	// OTEL_EXPORTER_OTLP_ENDPOINT variable.
+	TraceExporter string
+	// TraceFile is the file spans are appended to by the file exporter
+	// (TRACE_FILE)
+	TraceFile string
+	// TraceSampleRatio is the fraction of new traces sampled, from 0 to 1
+	// (TRACE_SAMPLE_RATIO)
+	TraceSampleRatio float64
+
+	// APIKeys are the static API keys accepted by the API server, set as
+	// comma-separated name:role:key entries (API_KEYS)
+	APIKeys []APIKey
+	// JWTSecret verifies HS256 bearer tokens; empty disables JWT
+	// authentication (JWT_SECRET)
+	JWTSecret string
+	// JWTIssuer and JWTAudience are checked in bearer tokens when set
+	// (JWT_ISSUER, JWT_AUDIENCE)
+	JWTIssuer   string
+	JWTAudience string
+	// AuthDisabled lets anonymous callers use every route, for local
+	// development only (AUTH_DISABLED)
+	AuthDisabled bool
+
+	// SearchKeyUID is the UID of the Meilisearch search API key tenant tokens
+	// are issued for; empty disables POST /api/auth/search-token
+	// (SEARCH_KEY_UID)
+	SearchKeyUID string
+	// SearchKey is the search API key itself; when empty it is fetched from
+	// Meilisearch by UID with the master key (SEARCH_KEY)
+	SearchKey string
+	// SearchTokenTTL is the default tenant token lifetime (SEARCH_TOKEN_TTL)
+	SearchTokenTTL time.Duration
+	// SearchTokenMaxTTL is the longest lifetime a caller may ask for
+	// (SEARCH_TOKEN_MAX_TTL)
+	SearchTokenMaxTTL time.Duration
+	// MeilisearchPublicURL is the Meilisearch address browsers use with
+	// tenant tokens; defaults to MeilisearchURL (MEILISEARCH_PUBLIC_URL)
+	MeilisearchPublicURL string
+
+	// RateLimit is the per-caller limit of API routes without their own, such
+	// as 10/s or 600/m; empty disables it (RATE_LIMIT)
+	RateLimit ratelimit.Limit
+	// RouteRateLimits are per-route limits, set as semicolon-separated
+	// pattern=limit entries (RATE_LIMIT_ROUTES)
+	RouteRateLimits map[string]ratelimit.Limit
+	// RateLimitTrustForwarded identifies clients by X-Forwarded-For
+	// instead of the connection address (RATE_LIMIT_TRUST_FORWARDED)
+	RateLimitTrustForwarded bool
+
+	// CORS is the cross-origin policy of the API server. Its lists are set as
+	// comma-separated values (CORS_ALLOWED_ORIGINS, CORS_ALLOWED_METHODS,
+	// CORS_ALLOWED_HEADERS, CORS_EXPOSED_HEADERS), along with
+	// CORS_ALLOW_CREDENTIALS and CORS_MAX_AGE
+	CORS cors.Policy
+
+	// Port is the port the API server listens on (PORT)
+	Port string
+	// UnitTolerance is the relative tolerance for matching sizes across
+	// inches and millimetres (UNIT_TOLERANCE); zero matches exactly
+	UnitTolerance float64
+	// Suggest tunes search-as-you-type separately from full search
+	// (SUGGEST_LIMIT, SUGGEST_MAX_LIMIT, SUGGEST_MAX_CATEGORIES)
+	Suggest service.SuggestConfig
+
+	// ReadTimeout bounds reading a whole request, body included (READ_TIMEOUT)
+	ReadTimeout time.Duration
+	// ReadHeaderTimeout bounds reading the request headers (READ_HEADER_TIMEOUT)
+	ReadHeaderTimeout time.Duration
+	// WriteTimeout bounds writing the response (WRITE_TIMEOUT)
+	WriteTimeout time.Duration
+	// IdleTimeout bounds how long keep-alive connections stay open (IDLE_TIMEOUT)
+	IdleTimeout time.Duration
+	// ShutdownDelay is how long the server reports not-ready before it stops
+	// accepting connections, so load balancers can take it out of rotation
+	// (SHUTDOWN_DELAY)
+	ShutdownDelay time.Duration
+	// ShutdownTimeout bounds how long in-flight requests may take to finish
+	// on shutdown (SHUTDOWN_TIMEOUT)
+	ShutdownTimeout time.Duration
+
+	// ReadyMinDocuments is the number of indexed documents below which the
+	// server is not ready (READY_MIN_DOCUMENTS)
+	ReadyMinDocuments int64
+	// ReadyMaxPendingTasks is the index task backlog above which the server
+	// is not ready (READY_MAX_PENDING_TASKS)
+	ReadyMaxPendingTasks int64
+	// HealthCacheTTL is how long a readiness result is reused (HEALTH_CACHE_TTL)
+	HealthCacheTTL time.Duration
+	// HealthTimeout bounds the Meilisearch calls of a readiness check (HEALTH_TIMEOUT)
+	HealthTimeout time.Duration
+
+	// DataFile is the catalog export loaded by the indexer (DATA_FILE)
+	DataFile string
+	// BatchSize is the number of documents uploaded per indexer batch (BATCH_SIZE)
+	BatchSize int
+	// PushgatewayURL is the Prometheus Pushgateway the indexer sends its
+	// metrics to when it finishes; empty disables pushing (PUSHGATEWAY_URL)
+	PushgatewayURL string
+}
+
+// APIKey is a static API key and the caller it identifies
+type APIKey struct {
+	Name string
+	Role auth.Role
+	Key  string
+}
+
+// Load reads the configuration from the environment
+func Load() (*Config, error) {
+	cfg := &Config{
+		MeilisearchURL: getEnv("MEILISEARCH_URL", DefaultMeilisearchURL),
+		MasterKey:      os.Getenv("MASTER_KEY"),
+		LogFormat:      getEnv("LOG_FORMAT", DefaultLogFormat),
+		LogLevel:       slog.LevelInfo,
+		TraceExporter:  getEnv("TRACE_EXPORTER", DefaultTraceExporter),
+		TraceFile:      getEnv("TRACE_FILE", DefaultTraceFile),
+		Port:           getEnv("PORT", DefaultPort),
+		DataFile:       getEnv("DATA_FILE", DefaultDataFile),
+		PushgatewayURL: os.Getenv("PUSHGATEWAY_URL"),
+		JWTSecret:      os.Getenv("JWT_SECRET"),
+		JWTIssuer:      os.Getenv("JWT_ISSUER"),
+		JWTAudience:    os.Getenv("JWT_AUDIENCE"),
+		SearchKeyUID:   os.Getenv("SEARCH_KEY_UID"),
+		SearchKey:      os.Getenv("SEARCH_KEY"),
+		BatchSize:      DefaultBatchSize,
+
+		ReadyMinDocuments:    service.DefaultReadyMinDocuments,
+		ReadyMaxPendingTasks: service.DefaultReadyMaxPendingTasks,
+		TraceSampleRatio:     DefaultTraceSample,
+		CORS:                 cors.DefaultPolicy(),
+		UnitTolerance:        service.DefaultUnitTolerance,
+		Suggest:              service.DefaultSuggestConfig(),
+	}
+	cfg.MeilisearchPublicURL = getEnv("MEILISEARCH_PUBLIC_URL", cfg.MeilisearchURL)
+
+	if cfg.LogFormat != LogFormatText && cfg.LogFormat != LogFormatJSON {
+		return nil, fmt.Errorf("invalid LOG_FORMAT %q: must be %s or %s", cfg.LogFormat, LogFormatText, LogFormatJSON)
+	}
+	if value := os.Getenv("LOG_LEVEL"); value != "" {
+		if err := cfg.LogLevel.UnmarshalText([]byte(value)); err != nil {
+			return nil, fmt.Errorf("invalid LOG_LEVEL %q: must be debug, info, warn or error", value)
+		}
+	}
+
+	switch cfg.TraceExporter {
+	case telemetry.ExporterNone, telemetry.ExporterOTLP, telemetry.ExporterStdout, telemetry.ExporterFile:
+	default:
+		return nil, fmt.Errorf("invalid TRACE_EXPORTER %q: must be %s, %s, %s or %s", cfg.TraceExporter,
+			telemetry.ExporterNone, telemetry.ExporterOTLP, telemetry.ExporterStdout, telemetry.ExporterFile)
+	}
+	if value := os.Getenv("TRACE_SAMPLE_RATIO"); value != "" {
+		ratio, err := strconv.ParseFloat(value, 64)
+		if err != nil || ratio < 0 || ratio > 1 {
+			return nil, fmt.Errorf("invalid TRACE_SAMPLE_RATIO %q: must be between 0 and 1", value)
+		}
+		cfg.TraceSampleRatio = ratio
+	}
+
+	if value := os.Getenv("API_KEYS"); value != "" {
+		keys, err := parseAPIKeys(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid API_KEYS: %w", err)
+		}
+		cfg.APIKeys = keys
+	}
+	if value := os.Getenv("AUTH_DISABLED"); value != "" {
+		disabled, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid AUTH_DISABLED %q: must be true or false", value)
+		}
+		cfg.AuthDisabled = disabled
+	}
+
+	if value := os.Getenv("RATE_LIMIT"); value != "" {
+		limit, err := ratelimit.ParseLimit(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid RATE_LIMIT: %w", err)
+		}
+		cfg.RateLimit = limit
+	}
+	if value := os.Getenv("RATE_LIMIT_ROUTES"); value != "" {
+		limits, err := parseRouteLimits(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid RATE_LIMIT_ROUTES: %w", err)
+		}
+		cfg.RouteRateLimits = limits
+	}
+	if value := os.Getenv("RATE_LIMIT_TRUST_FORWARDED"); value != "" {
+		trust, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid RATE_LIMIT_TRUST_FORWARDED %q: must be true or false", value)
+		}
+		cfg.RateLimitTrustForwarded = trust
+	}
+
+	lists := []struct {
+		key    string
+		target *[]string
+	}{
+		{"CORS_ALLOWED_ORIGINS", &cfg.CORS.AllowedOrigins},
+		{"CORS_ALLOWED_METHODS", &cfg.CORS.AllowedMethods},
+		{"CORS_ALLOWED_HEADERS", &cfg.CORS.AllowedHeaders},
+		{"CORS_EXPOSED_HEADERS", &cfg.CORS.ExposedHeaders},
+	}
+	for _, l := range lists {
+		if value, ok := os.LookupEnv(l.key); ok {
+			*l.target = splitList(value)
+		}
+	}
+	if value := os.Getenv("CORS_ALLOW_CREDENTIALS"); value != "" {
+		allow, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid CORS_ALLOW_CREDENTIALS %q: must be true or false", value)
+		}
+		cfg.CORS.AllowCredentials = allow
+	}
+	if cfg.CORS.AllowCredentials && slices.Contains(cfg.CORS.AllowedOrigins, "*") {
+		return nil, fmt.Errorf("invalid CORS_ALLOWED_ORIGINS: credentials cannot be allowed for any origin, list the origins instead")
+	}
+	for _, origin := range cfg.CORS.AllowedOrigins {
+		if origin != "*" && !strings.Contains(origin, "://") {
+			return nil, fmt.Errorf("invalid CORS_ALLOWED_ORIGINS entry %q: must be * or scheme://host such as https://*.example.com", origin)
+		}
+	}
+
+	if value := os.Getenv("UNIT_TOLERANCE"); value != "" {
+		tolerance, err := strconv.ParseFloat(value, 64)
+		if err != nil || tolerance < 0 || math.IsNaN(tolerance) || math.IsInf(tolerance, 0) {
+			return nil, fmt.Errorf("invalid UNIT_TOLERANCE %q: must be a non-negative number", value)
+		}
+		cfg.UnitTolerance = tolerance
+	}
+
+	if value := os.Getenv("BATCH_SIZE"); value != "" {
+		batchSize, err := strconv.Atoi(value)
+		if err != nil || batchSize <= 0 {
+			return nil, fmt.Errorf("invalid BATCH_SIZE %q: must be a positive integer", value)
+		}
+		cfg.BatchSize = batchSize
+	}
+
+	counts := []struct {
+		key    string
+		target *int64
+	}{
+		{"READY_MIN_DOCUMENTS", &cfg.ReadyMinDocuments},
+		{"READY_MAX_PENDING_TASKS", &cfg.ReadyMaxPendingTasks},
+	}
+	for _, c := range counts {
+		if value := os.Getenv(c.key); value != "" {
+			count, err := strconv.ParseInt(value, 10, 64)
+			if err != nil || count < 0 {
+				return nil, fmt.Errorf("invalid %s %q: must be a non-negative integer", c.key, value)
+			}
+			*c.target = count
+		}
+	}
+
+	suggestCounts := []struct {
+		key    string
+		target *int
+	}{
+		{"SUGGEST_LIMIT", &cfg.Suggest.Limit},
+		{"SUGGEST_MAX_LIMIT", &cfg.Suggest.MaxLimit},
+		{"SUGGEST_MAX_CATEGORIES", &cfg.Suggest.MaxCategories},
+	}
+	for _, c := range suggestCounts {
+		if value := os.Getenv(c.key); value != "" {
+			count, err := strconv.Atoi(value)
+			if err != nil || count <= 0 {
+				return nil, fmt.Errorf("invalid %s %q: must be a positive integer", c.key, value)
+			}
+			*c.target = count
+		}
+	}
+	if cfg.Suggest.Limit > cfg.Suggest.MaxLimit {
+		return nil, fmt.Errorf("invalid SUGGEST_LIMIT %d: must not exceed SUGGEST_MAX_LIMIT %d", cfg.Suggest.Limit, cfg.Suggest.MaxLimit)
+	}
+
+	durations := []struct {
+		key      string
+		fallback time.Duration
+		target   *time.Duration
+	}{
+		{"READ_TIMEOUT", DefaultReadTimeout, &cfg.ReadTimeout},
+		{"READ_HEADER_TIMEOUT", DefaultReadHeaderTimeout, &cfg.ReadHeaderTimeout},
+		{"WRITE_TIMEOUT", DefaultWriteTimeout, &cfg.WriteTimeout},
+		{"IDLE_TIMEOUT", DefaultIdleTimeout, &cfg.IdleTimeout},
+		{"SHUTDOWN_DELAY", DefaultShutdownDelay, &cfg.ShutdownDelay},
+		{"SHUTDOWN_TIMEOUT", DefaultShutdownTimeout, &cfg.ShutdownTimeout},
+		{"HEALTH_CACHE_TTL", service.DefaultHealthCacheTTL, &cfg.HealthCacheTTL},
+		{"HEALTH_TIMEOUT", service.DefaultHealthTimeout, &cfg.HealthTimeout},
+		{"SEARCH_TOKEN_TTL", service.DefaultSearchTokenTTL, &cfg.SearchTokenTTL},
+		{"SEARCH_TOKEN_MAX_TTL", service.DefaultSearchTokenMaxTTL, &cfg.SearchTokenMaxTTL},
+		{"CORS_MAX_AGE", cfg.CORS.MaxAge, &cfg.CORS.MaxAge},
+	}
+	for _, d := range durations {
+		value, err := getDuration(d.key, d.fallback)
+		if err != nil {
+			return nil, err
+		}
+		*d.target = value
+	}
+
+	if cfg.SearchKeyUID != "" && !meilisearch.IsValidUUID(cfg.SearchKeyUID) {
+		return nil, fmt.Errorf("invalid SEARCH_KEY_UID %q: must be the UUID of a Meilisearch API key", cfg.SearchKeyUID)
+	}
+	if cfg.SearchTokenTTL > cfg.SearchTokenMaxTTL {
+		return nil, fmt.Errorf("invalid SEARCH_TOKEN_TTL %s: must not exceed SEARCH_TOKEN_MAX_TTL %s", cfg.SearchTokenTTL, cfg.SearchTokenMaxTTL)
+	}
+
+	return cfg, nil
+}
+
+// NewLogger creates a logger writing to stderr in the configured format and
+// level
+func (c *Config) NewLogger() *slog.Logger {
+	options := &slog.HandlerOptions{Level: c.LogLevel}
+	if c.LogFormat == LogFormatJSON {
+		return slog.New(slog.NewJSONHandler(os.Stderr, options))
+	}
+	return slog.New(slog.NewTextHandler(os.Stderr, options))
+}
+
+// TracingOptions returns the tracing setup of the configured exporter for the
+// named service
+func (c *Config) TracingOptions(serviceName string) telemetry.Options {
+	return telemetry.Options{
+		Exporter:    c.TraceExporter,
+		File:        c.TraceFile,
+		SampleRatio: c.TraceSampleRatio,
+		ServiceName: serviceName,
+	}
+}
+
+// SearchTokenOptions returns the tenant token options set by the
+// configuration
+func (c *Config) SearchTokenOptions() []service.SearchTokenOption {
+	options := []service.SearchTokenOption{
+		service.WithSearchTokenTTL(c.SearchTokenTTL),
+		service.WithSearchTokenMaxTTL(c.SearchTokenMaxTTL),
+		service.WithSearchHost(c.MeilisearchPublicURL),
+	}
+	if c.SearchKey != "" {
+		options = append(options, service.WithSearchKey(c.SearchKey))
+	}
+	return options
+}
+
+// RateLimitOptions returns the rate limits set by the configuration
+func (c *Config) RateLimitOptions() []ratelimit.Option {
+	options := []ratelimit.Option{ratelimit.WithDefaultLimit(c.RateLimit)}
+	for pattern, limit := range c.RouteRateLimits {
+		options = append(options, ratelimit.WithRouteLimit(pattern, limit))
+	}
+	if c.RateLimitTrustForwarded {
+		options = append(options, ratelimit.WithTrustForwardedFor())
+	}
+	return options
+}
+
+// AuthOptions returns the credentials accepted by the API server
+func (c *Config) AuthOptions() []auth.Option {
+	var options []auth.Option
+	for _, key := range c.APIKeys {
+		options = append(options, auth.WithAPIKey(key.Name, key.Role, key.Key))
+	}
+	if c.JWTSecret != "" {
+		options = append(options, auth.WithJWT(c.JWTSecret, c.JWTIssuer, c.JWTAudience))
+	}
+	if c.AuthDisabled {
+		options = append(options, auth.WithDisabled())
+	}
+	return options
+}
+
+// NewClient creates a Meilisearch client for the configured server
+func (c *Config) NewClient() meilisearch.ServiceManager {
+	return meilisearch.New(c.MeilisearchURL, meilisearch.WithAPIKey(c.MasterKey))
+}
+
+// ProductOptions returns the product service options set by the configuration
+func (c *Config) ProductOptions() []service.ProductOption {
+	return []service.ProductOption{
+		service.WithUnitTolerance(c.UnitTolerance),
+		service.WithSuggestConfig(c.Suggest),
+	}
+}
+
+// HealthOptions returns the readiness check options set by the configuration
+func (c *Config) HealthOptions() []service.HealthOption {
+	return []service.HealthOption{
+		service.WithReadyMinDocuments(c.ReadyMinDocuments),
+		service.WithReadyMaxPendingTasks(c.ReadyMaxPendingTasks),
+		service.WithHealthCacheTTL(c.HealthCacheTTL),
+		service.WithHealthTimeout(c.HealthTimeout),
+	}
+}
+
+// getEnv returns an environment variable, or fallback when it is unset or empty
+func getEnv(key, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
+}
+
+// getDuration parses a duration such as "30s" from an environment variable,
+// or returns fallback when it is unset or empty
+func getDuration(key string, fallback time.Duration) (time.Duration, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback, nil
+	}
+	duration, err := time.ParseDuration(value)
+	if err != nil || duration < 0 {
+		return 0, fmt.Errorf("invalid %s %q: must be a non-negative duration such as 30s", key, value)
+	}
+	return duration, nil
+}
+
+// parseAPIKeys parses comma-separated name:role:key entries such as
+// "storefront:reader:abc123,backoffice:editor:def456"
+func parseAPIKeys(value string) ([]APIKey, error) {
+	var keys []APIKey
+	for i, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		parts := strings.SplitN(entry, ":", 3)
+		if len(parts) != 3 || parts[0] == "" || parts[2] == "" {
+			// The entry itself is not echoed, as it may hold a key
+			return nil, fmt.Errorf("entry %d must be name:role:key", i+1)
+		}
+		role, err := auth.ParseRole(parts[1])
+		if err != nil {
+			return nil, fmt.Errorf("key %q: %w", parts[0], err)
+		}
+		keys = append(keys, APIKey{Name: parts[0], Role: role, Key: parts[2]})
+	}
+	return keys, nil
+}
+
+// parseRouteLimits parses semicolon-separated pattern=limit entries such as
+// "POST /api/products/match-list=10/m;GET /api/products/suggest=20/s"
+func parseRouteLimits(value string) (map[string]ratelimit.Limit, error) {
+	limits := make(map[string]ratelimit.Limit)
+	for _, entry := range strings.Split(value, ";") {
+		if strings.TrimSpace(entry) == "" {
+			continue
+		}
+		pattern, rate, found := strings.Cut(entry, "=")
+		pattern = strings.TrimSpace(pattern)
+		if !found || pattern == "" {
+			return nil, fmt.Errorf("entry %q must be pattern=limit", entry)
+		}
+		limit, err := ratelimit.ParseLimit(rate)
+		if err != nil {
+			return nil, fmt.Errorf("route %q: %w", pattern, err)
+		}
+		limits[pattern] = limit
+	}
+	return limits, nil
+}
+
+// splitList splits a comma-separated list, dropping empty entries
+func splitList(value string) []string {
+	var values []string
+	for _, v := range strings.Split(value, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			values = append(values, v)
+		}
+	}
+	return values
+}
@@ -0,0 +1,101 @@
+// Package cors describes which cross-origin browser requests the API allows.
+package cors
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"meilisearch/internal/auth"
+)
+
+// Policy describes which cross-origin browser requests are allowed. Origins
+// are matched exactly, "*" allows any origin and a pattern such as
+// "https://*.example.com" allows its subdomains.
+type Policy struct {
+	AllowedOrigins   []string
+	AllowedMethods   []string
+	AllowedHeaders   []string
+	ExposedHeaders   []string
+	AllowCredentials bool
+	// MaxAge is how long browsers may cache a preflight result; zero omits
+	// the header
+	MaxAge time.Duration
+}
+
+// DefaultPolicy allows any origin to call the API without credentials,
+// sending the headers the API reads and reading the headers it sets: the
+// request ID, the rate limit headers and Retry-After
+func DefaultPolicy() Policy {
+	return Policy{
+		AllowedOrigins: []string{"*"},
+		AllowedMethods: []string{http.MethodGet, http.MethodPost, http.MethodDelete},
+		AllowedHeaders: []string{
+			"Content-Type", "Authorization", auth.APIKeyHeader, "X-Request-ID", "traceparent", "tracestate",
+		},
+		ExposedHeaders: []string{
+			"X-Request-ID", "X-RateLimit-Limit", "X-RateLimit-Remaining", "X-RateLimit-Reset", "Retry-After",
+		},
+		MaxAge: 10 * time.Minute,
+	}
+}
+
+// AllowsAnyOrigin reports whether the policy allows every origin
+func (p Policy) AllowsAnyOrigin() bool {
+	for _, allowed := range p.AllowedOrigins {
+		if allowed == "*" {
+			return true
+		}
+	}
+	return false
+}
+
+// AllowsOrigin reports whether an origin such as "https://shop.example.com"
+// is allowed
+func (p Policy) AllowsOrigin(origin string) bool {
+	origin = strings.ToLower(origin)
+	for _, allowed := range p.AllowedOrigins {
+		if matchOrigin(strings.ToLower(allowed), origin) {
+			return true
+		}
+	}
+	return false
+}
+
+// AllowsMethod reports whether a request method is allowed
+func (p Policy) AllowsMethod(method string) bool {
+	for _, allowed := range p.AllowedMethods {
+		if allowed == "*" || strings.EqualFold(allowed, method) {
+			return true
+		}
+	}
+	return false
+}
+
+// AllowsHeader reports whether a request header is allowed
+func (p Policy) AllowsHeader(header string) bool {
+	for _, allowed := range p.AllowedHeaders {
+		if allowed == "*" || strings.EqualFold(allowed, header) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchOrigin matches an origin against an allowed origin, where a "*" in
+// the host of the allowed origin stands for one or more subdomain labels
+func matchOrigin(allowed, origin string) bool {
+	if allowed == "*" || allowed == origin {
+		return true
+	}
+	prefix, suffix, found := strings.Cut(allowed, "*")
+	if !found || len(origin) <= len(prefix)+len(suffix) {
+		return false
+	}
+	if !strings.HasPrefix(origin, prefix) || !strings.HasSuffix(origin, suffix) {
+		return false
+	}
+	// The wildcard may only cover host labels, not a scheme, port or path
+	subdomain := origin[len(prefix) : len(origin)-len(suffix)]
+	return !strings.ContainsAny(subdomain, ":/@") && !strings.HasPrefix(subdomain, ".") && !strings.HasSuffix(subdomain, ".")
+}
@@ -0,0 +1,67 @@
+package cors
+
+import "testing"
+
+func TestMatchOrigin(t *testing.T) {
+	tests := []struct {
+		allowed string
+		origin  string
+		want    bool
+	}{
+		{"*", "https://shop.example.com", true},
+		{"https://shop.example.com", "https://shop.example.com", true},
+		{"https://shop.example.com", "http://shop.example.com", false},
+		{"https://shop.example.com", "https://shop.example.com:8443", false},
+		{"https://*.example.com", "https://shop.example.com", true},
+		{"https://*.example.com", "https://eu.shop.example.com", true},
+		{"https://*.example.com", "https://example.com", false},
+		{"https://*.example.com", "https://.example.com", false},
+		{"https://*.example.com", "https://evil.com/.example.com", false},
+		{"https://*.example.com", "https://evil.com:443.example.com", false},
+		{"https://*.example.com", "https://user@shop.example.com", false},
+		{"https://*.example.com", "https://shop.example.com.evil.com", false},
+		{"https://*.example.com", "http://shop.example.com", false},
+		{"https://*.example.com:8443", "https://shop.example.com:8443", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.allowed+" "+tt.origin, func(t *testing.T) {
+			if got := matchOrigin(tt.allowed, tt.origin); got != tt.want {
+				t.Errorf("matchOrigin(%q, %q) = %v, want %v", tt.allowed, tt.origin, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPolicyAllows(t *testing.T) {
+	policy := Policy{
+		AllowedOrigins: []string{"https://shop.example.com", "https://*.Dealers.example.com"},
+		AllowedMethods: []string{"GET", "POST"},
+		AllowedHeaders: []string{"Content-Type", "X-API-Key"},
+	}
+
+	tests := []struct {
+		name string
+		got  bool
+		want bool
+	}{
+		{"listed origin", policy.AllowsOrigin("https://shop.example.com"), true},
+		{"origin case", policy.AllowsOrigin("HTTPS://SHOP.EXAMPLE.COM"), true},
+		{"wildcard origin case", policy.AllowsOrigin("https://north.dealers.example.com"), true},
+		{"other origin", policy.AllowsOrigin("https://evil.example.com"), false},
+		{"any origin", policy.AllowsAnyOrigin(), false},
+		{"listed method", policy.AllowsMethod("post"), true},
+		{"other method", policy.AllowsMethod("DELETE"), false},
+		{"listed header", policy.AllowsHeader("x-api-key"), true},
+		{"other header", policy.AllowsHeader("X-Debug"), false},
+		{"default any origin", DefaultPolicy().AllowsAnyOrigin(), true},
+		{"header wildcard", Policy{AllowedHeaders: []string{"*"}}.AllowsHeader("X-Debug"), true},
+		{"method wildcard", Policy{AllowedMethods: []string{"*"}}.AllowsMethod("PATCH"), true},
+	}
+
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("%s = %v, want %v", tt.name, tt.got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package metrics
+
+import (
+	"context"
+	"time"
+
+	"github.com/meilisearch/meilisearch-go"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// indexStatsTimeout bounds the stats call made on each scrape
+const indexStatsTimeout = 2 * time.Second
+
+// indexCollector reports the document count of indexes, read from
+// Meilisearch on each scrape
+type indexCollector struct {
+	client    meilisearch.ServiceManager
+	uids      []string
+	documents *prometheus.Desc
+}
+
+// NewIndexCollector creates a collector of the document counts of the given
+// indexes. Indexes whose stats cannot be read are left out of the scrape.
+func NewIndexCollector(client meilisearch.ServiceManager, uids ...string) prometheus.Collector {
+	return &indexCollector{
+		client: client,
+		uids:   uids,
+		documents: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "index", "documents"),
+			"Documents in the Meilisearch index.",
+			[]string{"index"}, nil,
+		),
+	}
+}
+
+// Describe implements prometheus.Collector
+func (c *indexCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.documents
+}
+
+// Collect implements prometheus.Collector
+func (c *indexCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), indexStatsTimeout)
+	defer cancel()
+
+	for _, uid := range c.uids {
+		stats, err := c.client.Index(uid).GetStatsWithContext(ctx)
+		if err != nil {
+			continue
+		}
+		ch <- prometheus.MustNewConstMetric(c.documents, prometheus.GaugeValue,
+			float64(stats.NumberOfDocuments), uid)
+	}
+}
@@ -0,0 +1,95 @@
+// Package metrics defines the Prometheus metrics of the API server and the
+// indexer. Metrics are registered with the default Prometheus registry, which
+// also carries the Go runtime and process collectors.
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/push"
+)
+
+// namespace prefixes every metric name
+const namespace = "catalog"
+
+// UnmatchedRoute labels requests that matched no route
+const UnmatchedRoute = "unmatched"
+
+var (
+	// HTTPRequests counts served requests by method, route pattern and status
+	HTTPRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "http_requests_total",
+		Help:      "HTTP requests served, by method, route and status code.",
+	}, []string{"method", "route", "status"})
+
+	// HTTPRequestDuration observes request latency by method, route pattern
+	// and status
+	HTTPRequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "HTTP request latency, by method, route and status code.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+
+	// ZeroResultSearches counts searches that found nothing, by route
+	ZeroResultSearches = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "search_zero_results_total",
+		Help:      "Searches that returned no results, by route.",
+	}, []string{"route"})
+
+	// RateLimited counts requests rejected by the rate limiter, by route
+	RateLimited = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "rate_limited_requests_total",
+		Help:      "Requests rejected with 429 by the rate limiter, by route.",
+	}, []string{"route"})
+
+	// MeilisearchDuration observes Meilisearch call latency by operation
+	MeilisearchDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "meilisearch_request_duration_seconds",
+		Help:      "Meilisearch call latency, by operation.",
+		Buckets:   []float64{.001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5},
+	}, []string{"operation"})
+
+	// MeilisearchErrors counts failed Meilisearch calls by operation
+	MeilisearchErrors = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "meilisearch_errors_total",
+		Help:      "Failed Meilisearch calls, by operation. Missing documents are not counted.",
+	}, []string{"operation"})
+
+	// IndexerBatchDuration observes how long the indexer takes to upload a
+	// batch. The indexer metrics are pushed rather than registered, so the API
+	// server does not expose them.
+	IndexerBatchDuration = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "indexer_batch_duration_seconds",
+		Help:      "Time taken to upload one indexer batch.",
+		Buckets:   prometheus.ExponentialBuckets(0.05, 2, 10),
+	})
+
+	// IndexerDocuments counts documents uploaded by the indexer
+	IndexerDocuments = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "indexer_documents_total",
+		Help:      "Documents uploaded by the indexer.",
+	})
+)
+
+// indexerJob is the Pushgateway job name of the indexer
+const indexerJob = "catalog_indexer"
+
+// PushIndexerMetrics sends the indexer and Meilisearch call metrics to a
+// Prometheus Pushgateway. The indexer exits once done, so it cannot be
+// scraped like the API server.
+func PushIndexerMetrics(url string) error {
+	return push.New(url, indexerJob).
+		Collector(IndexerBatchDuration).
+		Collector(IndexerDocuments).
+		Collector(MeilisearchDuration).
+		Collector(MeilisearchErrors).
+		Push()
+}
@@ -0,0 +1,155 @@
+// Package ratelimit limits how often each client may call the API with token
+// buckets, kept in a Store that can be shared between server instances.
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Limit is a token bucket refilled at Rate tokens per second and holding at
+// most Burst tokens. The zero Limit means no limit.
+type Limit struct {
+	Rate  float64
+	Burst int
+}
+
+// IsZero reports whether the limit is unset
+func (l Limit) IsZero() bool {
+	return l.Rate <= 0 || l.Burst <= 0
+}
+
+// limitUnits maps ParseLimit units to their length
+var limitUnits = map[string]time.Duration{
+	"s": time.Second,
+	"m": time.Minute,
+	"h": time.Hour,
+}
+
+// ParseLimit parses a limit such as "10/s", "600/m" or "1000/h". The bucket
+// holds one unit's worth of requests, so a client may burst up to the count.
+func ParseLimit(value string) (Limit, error) {
+	count, unit, found := strings.Cut(strings.TrimSpace(value), "/")
+	period, ok := limitUnits[strings.TrimSpace(unit)]
+	n, err := strconv.Atoi(strings.TrimSpace(count))
+	if !found || !ok || err != nil || n <= 0 {
+		return Limit{}, fmt.Errorf("invalid rate limit %q: must be <count>/<s|m|h> such as 10/s", value)
+	}
+	return Limit{Rate: float64(n) / period.Seconds(), Burst: n}, nil
+}
+
+// Result is the outcome of taking a token from a bucket
+type Result struct {
+	Allowed bool
+	// Limit is the bucket size
+	Limit int
+	// Remaining is the number of whole tokens left
+	Remaining int
+	// RetryAfter is how long until a token is available; zero when allowed
+	RetryAfter time.Duration
+	// Reset is how long until the bucket is full again
+	Reset time.Duration
+}
+
+// Store keeps token buckets by key. The in-memory store suits a single
+// server; a shared implementation (e.g. Redis) lets replicas enforce one
+// limit together.
+type Store interface {
+	// Take removes a token from the bucket of key, creating it full when
+	// missing, and reports whether one was available
+	Take(ctx context.Context, key string, limit Limit) (Result, error)
+}
+
+// Limiter applies a default limit and per-route limits to clients
+type Limiter struct {
+	store          Store
+	defaultLimit   Limit
+	routes         map[string]Limit
+	trustForwarded bool
+}
+
+// Option configures a Limiter
+type Option func(*Limiter)
+
+// WithStore replaces the in-memory bucket store
+func WithStore(store Store) Option {
+	return func(l *Limiter) {
+		l.store = store
+	}
+}
+
+// WithDefaultLimit sets the limit shared by all routes without their own
+func WithDefaultLimit(limit Limit) Option {
+	return func(l *Limiter) {
+		l.defaultLimit = limit
+	}
+}
+
+// WithRouteLimit sets the limit of a route pattern such as
+// "POST /api/products/match-list". Each limited route has its own buckets.
+func WithRouteLimit(pattern string, limit Limit) Option {
+	return func(l *Limiter) {
+		l.routes[pattern] = limit
+	}
+}
+
+// WithTrustForwardedFor identifies clients by the first
+// X-Forwarded-For address. Only enable it behind a proxy that sets the
+// header, as clients can forge it otherwise.
+func WithTrustForwardedFor() Option {
+	return func(l *Limiter) {
+		l.trustForwarded = true
+	}
+}
+
+// NewLimiter creates a limiter backed by an in-memory store unless another
+// store is given
+func NewLimiter(opts ...Option) *Limiter {
+	l := &Limiter{routes: make(map[string]Limit)}
+	for _, opt := range opts {
+		opt(l)
+	}
+	if l.store == nil {
+		l.store = NewMemoryStore()
+	}
+	return l
+}
+
+// Take takes a token for a client calling a route. ok is false when the
+// route is not limited.
+func (l *Limiter) Take(ctx context.Context, route, client string) (result Result, ok bool, err error) {
+	key := client
+	limit, found := l.routes[route]
+	if found {
+		key = route + "|" + client
+	} else {
+		limit = l.defaultLimit
+	}
+	if limit.IsZero() {
+		return Result{}, false, nil
+	}
+	result, err = l.store.Take(ctx, key, limit)
+	return result, err == nil, err
+}
+
+// ClientIP returns the address of the client of a request
+func (l *Limiter) ClientIP(r *http.Request) string {
+	if l.trustForwarded {
+		if forwarded := r.Header.Get("X-Forwarded-For"); forwarded != "" {
+			first, _, _ := strings.Cut(forwarded, ",")
+			if ip := strings.TrimSpace(first); ip != "" {
+				return ip
+			}
+		}
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
@@ -0,0 +1,208 @@
+package ratelimit
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestParseLimit(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    Limit
+		wantErr bool
+	}{
+		{value: "10/s", want: Limit{Rate: 10, Burst: 10}},
+		{value: "600/m", want: Limit{Rate: 10, Burst: 600}},
+		{value: "3600/h", want: Limit{Rate: 1, Burst: 3600}},
+		{value: " 30 / m ", want: Limit{Rate: 0.5, Burst: 30}},
+		{value: "10", wantErr: true},
+		{value: "10/d", wantErr: true},
+		{value: "0/s", wantErr: true},
+		{value: "-5/s", wantErr: true},
+		{value: "ten/s", wantErr: true},
+		{value: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := ParseLimit(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseLimit(%q) error = %v, want error %v", tt.value, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseLimit(%q) = %+v, want %+v", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMemoryStoreTake(t *testing.T) {
+	store := NewMemoryStore()
+	limit := Limit{Rate: 3.0 / 60, Burst: 3}
+
+	tests := []struct {
+		allowed   bool
+		remaining int
+	}{
+		{true, 2},
+		{true, 1},
+		{true, 0},
+		{false, 0},
+		{false, 0},
+	}
+	for i, tt := range tests {
+		result, err := store.Take(context.Background(), "ip:10.0.0.1", limit)
+		if err != nil {
+			t.Fatalf("take %d: %v", i+1, err)
+		}
+		if result.Allowed != tt.allowed || result.Remaining != tt.remaining || result.Limit != 3 {
+			t.Errorf("take %d = %+v, want allowed %v with %d remaining", i+1, result, tt.allowed, tt.remaining)
+		}
+		if !result.Allowed && (result.RetryAfter <= 19*time.Second || result.RetryAfter > 20*time.Second) {
+			t.Errorf("take %d retry after %v, want about 20s", i+1, result.RetryAfter)
+		}
+		if result.Reset <= 0 || result.Reset > time.Minute {
+			t.Errorf("take %d reset %v, want up to a minute", i+1, result.Reset)
+		}
+	}
+
+	// Other keys have their own bucket
+	if result, _ := store.Take(context.Background(), "ip:10.0.0.2", limit); !result.Allowed {
+		t.Error("other client was limited")
+	}
+}
+
+func TestMemoryStoreRefill(t *testing.T) {
+	store := NewMemoryStore()
+	limit := Limit{Rate: 100, Burst: 1}
+
+	if result, _ := store.Take(context.Background(), "client", limit); !result.Allowed {
+		t.Fatal("first take was limited")
+	}
+	if result, _ := store.Take(context.Background(), "client", limit); result.Allowed {
+		t.Fatal("second take was allowed with an empty bucket")
+	}
+	time.Sleep(20 * time.Millisecond)
+	if result, _ := store.Take(context.Background(), "client", limit); !result.Allowed {
+		t.Error("take after refill was limited")
+	}
+}
+
+func TestLimiterTake(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    []Option
+		route   string
+		takes   int
+		limited bool
+		allowed bool
+	}{
+		{
+			name:  "no limits",
+			route: "GET /api/products",
+			takes: 5,
+		},
+		{
+			name:    "default limit",
+			opts:    []Option{WithDefaultLimit(Limit{Rate: 1, Burst: 2})},
+			route:   "GET /api/products",
+			takes:   3,
+			limited: true,
+			allowed: false,
+		},
+		{
+			name: "route limit replaces the default",
+			opts: []Option{
+				WithDefaultLimit(Limit{Rate: 1, Burst: 1}),
+				WithRouteLimit("POST /api/products/match-list", Limit{Rate: 1, Burst: 5}),
+			},
+			route:   "POST /api/products/match-list",
+			takes:   3,
+			limited: true,
+			allowed: true,
+		},
+		{
+			name:    "route limit does not apply to other routes",
+			opts:    []Option{WithRouteLimit("POST /api/products/match-list", Limit{Rate: 1, Burst: 1})},
+			route:   "GET /api/products",
+			takes:   3,
+			limited: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			limiter := NewLimiter(tt.opts...)
+			var (
+				result Result
+				ok     bool
+				err    error
+			)
+			for range tt.takes {
+				result, ok, err = limiter.Take(context.Background(), tt.route, "ip:10.0.0.1")
+				if err != nil {
+					t.Fatalf("Take() error = %v", err)
+				}
+			}
+			if ok != tt.limited {
+				t.Fatalf("limited = %v, want %v", ok, tt.limited)
+			}
+			if ok && result.Allowed != tt.allowed {
+				t.Errorf("allowed = %v, want %v", result.Allowed, tt.allowed)
+			}
+		})
+	}
+}
+
+func TestLimiterRouteBuckets(t *testing.T) {
+	limiter := NewLimiter(
+		WithDefaultLimit(Limit{Rate: 1, Burst: 1}),
+		WithRouteLimit("POST /api/products/match-list", Limit{Rate: 1, Burst: 1}),
+	)
+	ctx := context.Background()
+
+	limiter.Take(ctx, "GET /api/products", "ip:10.0.0.1")
+	if result, _, _ := limiter.Take(ctx, "POST /api/products/match-list", "ip:10.0.0.1"); !result.Allowed {
+		t.Error("route limit shared the default bucket")
+	}
+	if result, _, _ := limiter.Take(ctx, "GET /api/brands", "ip:10.0.0.1"); result.Allowed {
+		t.Error("routes without their own limit did not share the default bucket")
+	}
+}
+
+func TestClientIP(t *testing.T) {
+	tests := []struct {
+		name       string
+		trust      bool
+		remoteAddr string
+		forwarded  string
+		want       string
+	}{
+		{"remote address", false, "10.0.0.1:5123", "", "10.0.0.1"},
+		{"IPv6 remote address", false, "[::1]:5123", "", "::1"},
+		{"forwarded header ignored", false, "10.0.0.1:5123", "203.0.113.7", "10.0.0.1"},
+		{"trusted forwarded header", true, "10.0.0.1:5123", "203.0.113.7, 10.0.0.1", "203.0.113.7"},
+		{"trusted without forwarded header", true, "10.0.0.1:5123", "", "10.0.0.1"},
+		{"remote address without port", false, "10.0.0.1", "", "10.0.0.1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var opts []Option
+			if tt.trust {
+				opts = append(opts, WithTrustForwardedFor())
+			}
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			r.RemoteAddr = tt.remoteAddr
+			if tt.forwarded != "" {
+				r.Header.Set("X-Forwarded-For", tt.forwarded)
+			}
+			if got := NewLimiter(opts...).ClientIP(r); got != tt.want {
+				t.Errorf("ClientIP() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
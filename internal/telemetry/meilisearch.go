@@ -0,0 +1,279 @@
+package telemetry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"meilisearch/internal/metrics"
+
+	"github.com/meilisearch/meilisearch-go"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// InstrumentClient wraps a Meilisearch client so each call made by the
+// services gets a child span of the request span and is timed and counted
+// per operation for /metrics. Indexes returned by Index are instrumented as
+// well.
+func InstrumentClient(client meilisearch.ServiceManager) meilisearch.ServiceManager {
+	return &instrumentedClient{ServiceManager: client}
+}
+
+// start opens a client span for one Meilisearch call. The returned function
+// ends the span, adding the given attributes, and records the latency and
+// outcome of the call.
+func start(ctx context.Context, operation string, attrs ...attribute.KeyValue) (context.Context, func(error, ...attribute.KeyValue)) {
+	begin := time.Now()
+	attrs = append(attrs,
+		semconv.DBSystemKey.String("meilisearch"),
+		attribute.String("meilisearch.operation", operation),
+	)
+	ctx, span := Tracer().Start(ctx, "meilisearch."+operation,
+		trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(attrs...))
+
+	return ctx, func(err error, attrs ...attribute.KeyValue) {
+		metrics.MeilisearchDuration.WithLabelValues(operation).Observe(time.Since(begin).Seconds())
+		// A missing document is an expected outcome of a lookup
+		if err != nil && !isNotFound(err) {
+			metrics.MeilisearchErrors.WithLabelValues(operation).Inc()
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+		span.SetAttributes(attrs...)
+		span.End()
+	}
+}
+
+// isNotFound reports whether a Meilisearch error is a 404
+func isNotFound(err error) bool {
+	var meiliErr *meilisearch.Error
+	return errors.As(err, &meiliErr) && meiliErr.StatusCode == http.StatusNotFound
+}
+
+// searchAttributes describes a search request on a span
+func searchAttributes(query string, request *meilisearch.SearchRequest) []attribute.KeyValue {
+	attrs := []attribute.KeyValue{attribute.String("meilisearch.query", query)}
+	if request == nil {
+		return attrs
+	}
+	if filter := filterText(request.Filter); filter != "" {
+		attrs = append(attrs, attribute.String("meilisearch.filter", filter))
+	}
+	if len(request.Sort) > 0 {
+		attrs = append(attrs, attribute.StringSlice("meilisearch.sort", request.Sort))
+	}
+	if request.Limit > 0 {
+		attrs = append(attrs, attribute.Int64("meilisearch.limit", request.Limit))
+	}
+	if request.HitsPerPage > 0 {
+		attrs = append(attrs, attribute.Int64("meilisearch.hits_per_page", request.HitsPerPage))
+	}
+	return attrs
+}
+
+// filterText formats a search or document filter for a span attribute
+func filterText(filter interface{}) string {
+	switch f := filter.(type) {
+	case nil:
+		return ""
+	case string:
+		return f
+	case []string:
+		return strings.Join(f, " AND ")
+	default:
+		return fmt.Sprint(f)
+	}
+}
+
+// instrumentedClient instruments the client calls used by the services. The
+// methods without a context delegate to their context variants so each call
+// is recorded once.
+type instrumentedClient struct {
+	meilisearch.ServiceManager
+}
+
+func (c *instrumentedClient) Index(uid string) meilisearch.IndexManager {
+	return &instrumentedIndex{IndexManager: c.ServiceManager.Index(uid), uid: uid}
+}
+
+func (c *instrumentedClient) MultiSearch(queries *meilisearch.MultiSearchRequest) (*meilisearch.MultiSearchResponse, error) {
+	return c.MultiSearchWithContext(context.Background(), queries)
+}
+
+func (c *instrumentedClient) MultiSearchWithContext(ctx context.Context, queries *meilisearch.MultiSearchRequest) (resp *meilisearch.MultiSearchResponse, err error) {
+	var texts []string
+	for _, query := range queries.Queries {
+		texts = append(texts, query.Query)
+	}
+	ctx, end := start(ctx, "multi_search",
+		attribute.Int("meilisearch.queries", len(queries.Queries)),
+		attribute.StringSlice("meilisearch.query", texts),
+	)
+	defer func() { end(err) }()
+	return c.ServiceManager.MultiSearchWithContext(ctx, queries)
+}
+
+func (c *instrumentedClient) Health() (*meilisearch.Health, error) {
+	return c.HealthWithContext(context.Background())
+}
+
+func (c *instrumentedClient) HealthWithContext(ctx context.Context) (resp *meilisearch.Health, err error) {
+	ctx, end := start(ctx, "health")
+	defer func() { end(err) }()
+	return c.ServiceManager.HealthWithContext(ctx)
+}
+
+func (c *instrumentedClient) GetTask(taskUID int64) (*meilisearch.Task, error) {
+	return c.GetTaskWithContext(context.Background(), taskUID)
+}
+
+func (c *instrumentedClient) GetTaskWithContext(ctx context.Context, taskUID int64) (resp *meilisearch.Task, err error) {
+	ctx, end := start(ctx, "get_task", attribute.Int64("meilisearch.task_uid", taskUID))
+	defer func() { end(err) }()
+	return c.ServiceManager.GetTaskWithContext(ctx, taskUID)
+}
+
+func (c *instrumentedClient) GetTasks(param *meilisearch.TasksQuery) (*meilisearch.TaskResult, error) {
+	return c.GetTasksWithContext(context.Background(), param)
+}
+
+func (c *instrumentedClient) GetTasksWithContext(ctx context.Context, param *meilisearch.TasksQuery) (resp *meilisearch.TaskResult, err error) {
+	ctx, end := start(ctx, "get_tasks")
+	defer func() { end(err) }()
+	return c.ServiceManager.GetTasksWithContext(ctx, param)
+}
+
+func (c *instrumentedClient) GetKey(identifier string) (*meilisearch.Key, error) {
+	return c.GetKeyWithContext(context.Background(), identifier)
+}
+
+func (c *instrumentedClient) GetKeyWithContext(ctx context.Context, identifier string) (resp *meilisearch.Key, err error) {
+	ctx, end := start(ctx, "get_key", attribute.String("meilisearch.key_uid", identifier))
+	defer func() { end(err) }()
+	return c.ServiceManager.GetKeyWithContext(ctx, identifier)
+}
+
+// instrumentedIndex instruments the index calls used by the services and the
+// indexer
+type instrumentedIndex struct {
+	meilisearch.IndexManager
+	uid string
+}
+
+// start opens a span for a call on this index
+func (i *instrumentedIndex) start(ctx context.Context, operation string, attrs ...attribute.KeyValue) (context.Context, func(error, ...attribute.KeyValue)) {
+	return start(ctx, operation, append(attrs, attribute.String("meilisearch.index", i.uid))...)
+}
+
+func (i *instrumentedIndex) Search(query string, request *meilisearch.SearchRequest) (*meilisearch.SearchResponse, error) {
+	return i.SearchWithContext(context.Background(), query, request)
+}
+
+func (i *instrumentedIndex) SearchWithContext(ctx context.Context, query string, request *meilisearch.SearchRequest) (resp *meilisearch.SearchResponse, err error) {
+	ctx, end := i.start(ctx, "search", searchAttributes(query, request)...)
+	defer func() {
+		if resp == nil {
+			end(err)
+			return
+		}
+		end(err,
+			attribute.Int("meilisearch.hits", len(resp.Hits)),
+			attribute.Int64("meilisearch.total_hits", resp.EstimatedTotalHits+resp.TotalHits),
+			attribute.Int64("meilisearch.processing_time_ms", resp.ProcessingTimeMs),
+		)
+	}()
+	return i.IndexManager.SearchWithContext(ctx, query, request)
+}
+
+func (i *instrumentedIndex) GetDocument(identifier string, request *meilisearch.DocumentQuery, documentPtr interface{}) error {
+	return i.GetDocumentWithContext(context.Background(), identifier, request, documentPtr)
+}
+
+func (i *instrumentedIndex) GetDocumentWithContext(ctx context.Context, identifier string, request *meilisearch.DocumentQuery, documentPtr interface{}) (err error) {
+	ctx, end := i.start(ctx, "get_document", attribute.String("meilisearch.document_id", identifier))
+	defer func() { end(err) }()
+	return i.IndexManager.GetDocumentWithContext(ctx, identifier, request, documentPtr)
+}
+
+func (i *instrumentedIndex) GetDocuments(param *meilisearch.DocumentsQuery, resp *meilisearch.DocumentsResult) error {
+	return i.GetDocumentsWithContext(context.Background(), param, resp)
+}
+
+func (i *instrumentedIndex) GetDocumentsWithContext(ctx context.Context, param *meilisearch.DocumentsQuery, resp *meilisearch.DocumentsResult) (err error) {
+	var attrs []attribute.KeyValue
+	if param != nil {
+		if filter := filterText(param.Filter); filter != "" {
+			attrs = append(attrs, attribute.String("meilisearch.filter", filter))
+		}
+		attrs = append(attrs, attribute.Int64("meilisearch.limit", param.Limit))
+	}
+	ctx, end := i.start(ctx, "get_documents", attrs...)
+	defer func() { end(err) }()
+	return i.IndexManager.GetDocumentsWithContext(ctx, param, resp)
+}
+
+func (i *instrumentedIndex) AddDocuments(documentsPtr interface{}, primaryKey ...string) (*meilisearch.TaskInfo, error) {
+	return i.AddDocumentsWithContext(context.Background(), documentsPtr, primaryKey...)
+}
+
+func (i *instrumentedIndex) AddDocumentsWithContext(ctx context.Context, documentsPtr interface{}, primaryKey ...string) (resp *meilisearch.TaskInfo, err error) {
+	ctx, end := i.start(ctx, "add_documents")
+	defer func() { end(err) }()
+	return i.IndexManager.AddDocumentsWithContext(ctx, documentsPtr, primaryKey...)
+}
+
+func (i *instrumentedIndex) UpdateDocuments(documentsPtr interface{}, primaryKey ...string) (*meilisearch.TaskInfo, error) {
+	return i.UpdateDocumentsWithContext(context.Background(), documentsPtr, primaryKey...)
+}
+
+func (i *instrumentedIndex) UpdateDocumentsWithContext(ctx context.Context, documentsPtr interface{}, primaryKey ...string) (resp *meilisearch.TaskInfo, err error) {
+	ctx, end := i.start(ctx, "update_documents")
+	defer func() { end(err) }()
+	return i.IndexManager.UpdateDocumentsWithContext(ctx, documentsPtr, primaryKey...)
+}
+
+func (i *instrumentedIndex) DeleteDocument(identifier string) (*meilisearch.TaskInfo, error) {
+	return i.DeleteDocumentWithContext(context.Background(), identifier)
+}
+
+func (i *instrumentedIndex) DeleteDocumentWithContext(ctx context.Context, identifier string) (resp *meilisearch.TaskInfo, err error) {
+	ctx, end := i.start(ctx, "delete_document", attribute.String("meilisearch.document_id", identifier))
+	defer func() { end(err) }()
+	return i.IndexManager.DeleteDocumentWithContext(ctx, identifier)
+}
+
+func (i *instrumentedIndex) GetStats() (*meilisearch.StatsIndex, error) {
+	return i.GetStatsWithContext(context.Background())
+}
+
+func (i *instrumentedIndex) GetStatsWithContext(ctx context.Context) (resp *meilisearch.StatsIndex, err error) {
+	ctx, end := i.start(ctx, "get_stats")
+	defer func() { end(err) }()
+	return i.IndexManager.GetStatsWithContext(ctx)
+}
+
+func (i *instrumentedIndex) UpdateSettings(request *meilisearch.Settings) (*meilisearch.TaskInfo, error) {
+	return i.UpdateSettingsWithContext(context.Background(), request)
+}
+
+func (i *instrumentedIndex) UpdateSettingsWithContext(ctx context.Context, request *meilisearch.Settings) (resp *meilisearch.TaskInfo, err error) {
+	ctx, end := i.start(ctx, "update_settings")
+	defer func() { end(err) }()
+	return i.IndexManager.UpdateSettingsWithContext(ctx, request)
+}
+
+func (i *instrumentedIndex) UpdateSynonyms(request *map[string][]string) (*meilisearch.TaskInfo, error) {
+	return i.UpdateSynonymsWithContext(context.Background(), request)
+}
+
+func (i *instrumentedIndex) UpdateSynonymsWithContext(ctx context.Context, request *map[string][]string) (resp *meilisearch.TaskInfo, err error) {
+	ctx, end := i.start(ctx, "update_synonyms")
+	defer func() { end(err) }()
+	return i.IndexManager.UpdateSynonymsWithContext(ctx, request)
+}
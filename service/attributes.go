@@ -0,0 +1,242 @@
+package service
+
+import (
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Attribute fields added to product documents at index time
+const (
+	FieldThicknessMM = "thickness_mm"
+	FieldLengthMM    = "length_mm"
+	FieldHeightMM    = "height_mm"
+	FieldLengthIN    = "length_in"
+	FieldHeightIN    = "height_in"
+	FieldLoadKG      = "load_kg"
+	FieldWeightG     = "weight_g"
+	FieldVolumeML    = "volume_ml"
+	FieldSheetSize   = "sheet_size"
+	FieldCrank       = "crank"
+	FieldCloseType   = "close_type"
+	FieldFinish      = "finish"
+)
+
+// Close types of channels, hinges and door slides
+const (
+	CloseSoft       = "soft"
+	CloseNormal     = "normal"
+	ClosePushToOpen = "push_to_open"
+)
+
+// AttributeFields lists the extracted attribute fields, all of which are filterable
+var AttributeFields = []string{
+	FieldThicknessMM,
+	FieldLengthMM,
+	FieldHeightMM,
+	FieldLengthIN,
+	FieldHeightIN,
+	FieldLoadKG,
+	FieldWeightG,
+	FieldVolumeML,
+	FieldSheetSize,
+	FieldCrank,
+	FieldCloseType,
+	FieldFinish,
+}
+
+// boardCategories are sold as sheets, so a millimetre value is a thickness
+var boardCategories = map[string]bool{
+	"Plywood":         true,
+	"HDHMR":           true,
+	"MDF":             true,
+	"WPC":             true,
+	"Inner Laminates": true,
+	"Outer Laminates": true,
+}
+
+// packCategories are consumables, so a mass is a pack weight rather than a load rating
+var packCategories = map[string]bool{
+	"Adhesives":        true,
+	"Screws and Nails": true,
+}
+
+// finishCategories are hardware whose names state a finish; elsewhere words
+// such as "Silver" or "White" name a product line or colour
+var finishCategories = map[string]bool{
+	"Channels":         true,
+	"Hinges":           true,
+	"Tandems":          true,
+	"Screws and Nails": true,
+}
+
+var (
+	// ratedFinishPattern finds a finish following a load rating, as on tandems
+	// ("50 KG-WH", "40 Kg-Graphite Grey")
+	ratedFinishPattern = regexp.MustCompile(`(?i)\bkg\s*-\s*([a-z][a-z ]*[a-z])`)
+	// finishPattern finds finishes named elsewhere in a name
+	finishPattern = regexp.MustCompile(`(?i)\b(zinc|zw|ss\s*304|ss|graphite grey|metallic anthracite|silver|grey)\b`)
+)
+
+// nominalPairTolerance bounds the difference between the inch and millimetre
+// sizes a name gives for the same dimension
+const nominalPairTolerance = 0.05
+
+// crankPattern finds hinge crank sizes such as "0 crank" or "16 crank"
+var crankPattern = regexp.MustCompile(`(?i)\b(\d+)\s*crank\b`)
+
+// closeTypePattern finds the closing mechanism, written "Soft" or "Soft Close"
+// for channels and hinges alike
+var closeTypePattern = regexp.MustCompile(`(?i)\b(soft|normal|push\s+to\s+open)\b`)
+
+// ExtractAttributes derives canonical attribute fields from a product name.
+// The category decides what a bare dimension means: a thickness for boards,
+// a height then length for tandems and a length for other hardware.
+func ExtractAttributes(name, category string) map[string]interface{} {
+	attributes := make(map[string]interface{})
+
+	// inches holds imperial sizes converted to millimetres, nominalInches the
+	// sizes as written
+	var millimetres, inches, nominalInches []float64
+	for _, m := range scanMeasurements(name) {
+		switch {
+		case m.kind == KindLength && m.unit == "mm":
+			millimetres = append(millimetres, m.canonical)
+		case m.kind == KindLength:
+			inches = append(inches, m.canonical)
+			nominalInches = append(nominalInches, roundCanonical(m.canonical/25.4))
+		case m.kind == KindMass && packCategories[category]:
+			setOnce(attributes, FieldWeightG, roundCanonical(m.canonical*1000))
+		case m.kind == KindMass:
+			setOnce(attributes, FieldLoadKG, m.canonical)
+		case m.kind == KindVolume:
+			setOnce(attributes, FieldVolumeML, m.canonical)
+		}
+	}
+
+	// Names often give both units ("18 in- 450 mm"); millimetres are the
+	// nominal size, converted imperial values are only a fallback
+	lengths := millimetres
+	if len(lengths) == 0 {
+		lengths = inches
+	}
+
+	switch {
+	case boardCategories[category]:
+		if len(lengths) > 0 {
+			attributes[FieldThicknessMM] = lengths[0]
+		}
+		if match := sheetSizePattern.FindStringSubmatch(name); match != nil {
+			attributes[FieldSheetSize] = normalizeSheetSize(match[1], match[2])
+		}
+	case category == "Tandems" && len(lengths) >= 2:
+		attributes[FieldHeightMM] = lengths[0]
+		attributes[FieldLengthMM] = lengths[1]
+		// Tandem names pair every size: "4 in (90mm) x 16 in (400 mm)"
+		if len(nominalInches) >= 2 {
+			attributes[FieldHeightIN] = nominalInches[0]
+			attributes[FieldLengthIN] = nominalInches[1]
+		}
+	case len(lengths) > 0:
+		attributes[FieldLengthMM] = lengths[0]
+		// Only keep the inch size when it describes the same dimension, as in
+		// "18 in- 450 mm" or a name that gives inches alone, and not a second
+		// dimension as in "450mm, 4 Inch"
+		if len(nominalInches) > 0 && (len(millimetres) == 0 || sameNominalSize(inches[0], millimetres[0])) {
+			attributes[FieldLengthIN] = nominalInches[0]
+		}
+	}
+
+	if match := crankPattern.FindStringSubmatch(name); match != nil {
+		if crank, err := strconv.Atoi(match[1]); err == nil {
+			attributes[FieldCrank] = crank
+		}
+	}
+
+	if finishCategories[category] {
+		if finish := extractFinish(name); finish != "" {
+			attributes[FieldFinish] = finish
+		}
+	}
+
+	if match := closeTypePattern.FindStringSubmatch(name); match != nil {
+		switch strings.ToLower(match[1][:1]) {
+		case "s":
+			attributes[FieldCloseType] = CloseSoft
+		case "n":
+			attributes[FieldCloseType] = CloseNormal
+		default:
+			attributes[FieldCloseType] = ClosePushToOpen
+		}
+	}
+
+	return attributes
+}
+
+// EnrichDocument adds extracted attribute fields, the variant group, a numeric
+// price and, when the matchers are given, the brand and category hierarchy to
+// a raw product document
+func EnrichDocument(doc map[string]interface{}, brands *BrandMatcher, categories *CategoryMatcher) {
+	name, _ := doc["name"].(string)
+	category, _ := doc["category_name"].(string)
+	for field, value := range ExtractAttributes(name, category) {
+		doc[field] = value
+	}
+	doc[FieldVariantGroup] = VariantGroupKey(name, category)
+	if price, ok := doc["selling_price"].(string); ok {
+		if value, err := strconv.ParseFloat(strings.TrimSpace(price), 64); err == nil {
+			doc[FieldPrice] = value
+		}
+	}
+	if brands != nil {
+		if brand := brands.Match(name); brand != "" {
+			doc[FieldBrand] = brand
+		}
+	}
+	if categories != nil {
+		if path := categories.Path(name, category); len(path) > 0 {
+			doc[FieldCategory] = CategoryLevelValues(path)
+		}
+	}
+}
+
+// extractFinish returns the finish stated in a product name, with words
+// capitalized and abbreviations such as "ZW" or "SS304" upper-cased
+func extractFinish(name string) string {
+	// Model codes follow a semicolon and notes follow a line break
+	if idx := strings.IndexAny(name, ";\n"); idx >= 0 {
+		name = name[:idx]
+	}
+
+	match := ratedFinishPattern.FindStringSubmatch(name)
+	if match == nil {
+		match = finishPattern.FindStringSubmatch(name)
+	}
+	if match == nil {
+		return ""
+	}
+
+	words := strings.Fields(match[1])
+	for i, word := range words {
+		if len(word) <= 3 || strings.ContainsAny(word, "0123456789") {
+			words[i] = strings.ToUpper(word)
+		} else {
+			words[i] = strings.ToUpper(word[:1]) + strings.ToLower(word[1:])
+		}
+	}
+	return strings.Join(words, " ")
+}
+
+// sameNominalSize reports whether two millimetre values describe the same
+// nominal size, e.g. 457.2 mm (18 in) and 450 mm
+func sameNominalSize(a, b float64) bool {
+	return math.Abs(a-b) <= b*nominalPairTolerance
+}
+
+// setOnce sets a field unless an earlier measurement already set it
+func setOnce(attributes map[string]interface{}, field string, value interface{}) {
+	if _, ok := attributes[field]; !ok {
+		attributes[field] = value
+	}
+}
@@ -0,0 +1,209 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"meilisearch/dto"
+
+	"github.com/meilisearch/meilisearch-go"
+)
+
+// DefaultBrandsFile is the brand dictionary shipped with the repository
+const DefaultBrandsFile = "config/brands.json"
+
+// FieldBrand is the brand field added to product documents at index time
+const FieldBrand = "brand"
+
+// LoadBrandDictionary reads a brand dictionary from disk
+func LoadBrandDictionary(path string) (*dto.BrandDictionary, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var dictionary dto.BrandDictionary
+	if err := json.Unmarshal(data, &dictionary); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return &dictionary, nil
+}
+
+// brandPattern matches one alias of a brand as a whole word
+type brandPattern struct {
+	brand   string
+	alias   string
+	pattern *regexp.Regexp
+}
+
+// BrandMatcher finds brands in product names using the brand dictionary
+type BrandMatcher struct {
+	brands   []dto.Brand
+	patterns []brandPattern
+}
+
+// NewBrandMatcher compiles the aliases of a brand dictionary
+func NewBrandMatcher(dictionary *dto.BrandDictionary) *BrandMatcher {
+	matcher := &BrandMatcher{brands: dictionary.Brands}
+	for _, brand := range dictionary.Brands {
+		aliases := append([]string{brand.Name}, brand.Aliases...)
+		for _, alias := range aliases {
+			words := strings.Fields(regexp.QuoteMeta(strings.ToLower(alias)))
+			if len(words) == 0 {
+				continue
+			}
+			matcher.patterns = append(matcher.patterns, brandPattern{
+				brand:   brand.Name,
+				alias:   alias,
+				pattern: regexp.MustCompile(`(?i)\b` + strings.Join(words, `\s+`) + `\b`),
+			})
+		}
+	}
+	return matcher
+}
+
+// Match returns the brand mentioned first in a product name, preferring the
+// longest alias at that position, or "" when no brand is recognized
+func (m *BrandMatcher) Match(name string) string {
+	brand, start, length := "", -1, 0
+	for _, p := range m.patterns {
+		loc := p.pattern.FindStringIndex(name)
+		if loc == nil {
+			continue
+		}
+		if start < 0 || loc[0] < start || (loc[0] == start && loc[1]-loc[0] > length) {
+			brand, start, length = p.brand, loc[0], loc[1]-loc[0]
+		}
+	}
+	return brand
+}
+
+// Aliases returns the dictionary aliases of a brand
+func (m *BrandMatcher) Aliases(name string) []string {
+	for _, brand := range m.brands {
+		if strings.EqualFold(brand.Name, name) {
+			return brand.Aliases
+		}
+	}
+	return nil
+}
+
+// BrandService lists brands present in the product index
+type BrandService struct {
+	client meilisearch.ServiceManager
+	index  meilisearch.IndexManager
+	path   string
+
+	once    sync.Once
+	matcher *BrandMatcher
+	loadErr error
+}
+
+// NewBrandService creates a brand service using the given brand dictionary
+// file. The file is loaded on first use.
+func NewBrandService(client meilisearch.ServiceManager, path string) *BrandService {
+	return &BrandService{
+		client: client,
+		index:  client.Index(ProductIndexUID),
+		path:   path,
+	}
+}
+
+// ListBrands returns every brand among active products with its product
+// count per category, ordered by product count
+func (s *BrandService) ListBrands(ctx context.Context) ([]dto.BrandSummary, error) {
+	matcher, err := s.loadMatcher()
+	if err != nil {
+		return nil, err
+	}
+
+	overview, err := s.index.SearchWithContext(ctx, "", &meilisearch.SearchRequest{
+		Limit:                1,
+		AttributesToRetrieve: []string{"id"},
+		Filter:               []string{"is_active = 1"},
+		Facets:               []string{FieldBrand, "category_name"},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	brandTotals := facetCounts(overview.FacetDistribution, FieldBrand)
+	categoryTotals := facetCounts(overview.FacetDistribution, "category_name")
+	if len(brandTotals) == 0 {
+		return []dto.BrandSummary{}, nil
+	}
+
+	// One query per category yields the brand distribution within it
+	categories := make([]string, 0, len(categoryTotals))
+	queries := make([]*meilisearch.SearchRequest, 0, len(categoryTotals))
+	for category := range categoryTotals {
+		categories = append(categories, category)
+		queries = append(queries, &meilisearch.SearchRequest{
+			IndexUID:             ProductIndexUID,
+			Limit:                1,
+			AttributesToRetrieve: []string{"id"},
+			Filter:               []string{"is_active = 1", "category_name = " + quoteFilterValue(category)},
+			Facets:               []string{FieldBrand},
+		})
+	}
+	perCategory, err := s.client.MultiSearchWithContext(ctx, &meilisearch.MultiSearchRequest{Queries: queries})
+	if err != nil {
+		return nil, err
+	}
+
+	byBrand := make(map[string][]dto.CategoryCount)
+	for i, result := range perCategory.Results {
+		if i >= len(categories) {
+			break
+		}
+		for brand, count := range facetCounts(result.FacetDistribution, FieldBrand) {
+			byBrand[brand] = append(byBrand[brand], dto.CategoryCount{Category: categories[i], Count: count})
+		}
+	}
+
+	brands := make([]dto.BrandSummary, 0, len(brandTotals))
+	for brand, total := range brandTotals {
+		counts := byBrand[brand]
+		sort.Slice(counts, func(i, j int) bool {
+			if counts[i].Count != counts[j].Count {
+				return counts[i].Count > counts[j].Count
+			}
+			return counts[i].Category < counts[j].Category
+		})
+		if counts == nil {
+			counts = []dto.CategoryCount{}
+		}
+		brands = append(brands, dto.BrandSummary{
+			Name:         brand,
+			Aliases:      matcher.Aliases(brand),
+			ProductCount: total,
+			Categories:   counts,
+		})
+	}
+	sort.Slice(brands, func(i, j int) bool {
+		if brands[i].ProductCount != brands[j].ProductCount {
+			return brands[i].ProductCount > brands[j].ProductCount
+		}
+		return brands[i].Name < brands[j].Name
+	})
+	return brands, nil
+}
+
+// loadMatcher loads the brand dictionary once
+func (s *BrandService) loadMatcher() (*BrandMatcher, error) {
+	s.once.Do(func() {
+		dictionary, err := LoadBrandDictionary(s.path)
+		if err != nil {
+			s.loadErr = err
+			return
+		}
+		s.matcher = NewBrandMatcher(dictionary)
+	})
+	return s.matcher, s.loadErr
+}
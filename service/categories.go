@@ -0,0 +1,329 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"meilisearch/dto"
+
+	"github.com/meilisearch/meilisearch-go"
+)
+
+// DefaultCategoriesFile is the category hierarchy shipped with the repository
+const DefaultCategoriesFile = "config/categories.json"
+
+// FieldCategory holds the hierarchical category levels of a product, stored
+// as category.lvl0 ("Hardware"), category.lvl1 ("Hardware > Channels"), ...
+const FieldCategory = "category"
+
+// CategoryLevels is the number of hierarchy levels indexed per product
+const CategoryLevels = 3
+
+// CategoryPathSeparator joins the names of a category path
+const CategoryPathSeparator = " > "
+
+// OtherCategory is the top-level category of catalog categories missing from
+// the hierarchy
+const OtherCategory = "Other"
+
+// ErrCategoryNotFound is returned for a category path not in the hierarchy
+var ErrCategoryNotFound = errors.New("category not found")
+
+// CategoryLevelFields lists the filterable category level fields
+var CategoryLevelFields = categoryLevelFields()
+
+// categoryLevelFields returns the field names of every category level
+func categoryLevelFields() []string {
+	fields := make([]string, CategoryLevels)
+	for level := range fields {
+		fields[level] = categoryLevelField(level)
+	}
+	return fields
+}
+
+// categoryLevelField returns the field name of one category level
+func categoryLevelField(level int) string {
+	return FieldCategory + ".lvl" + strconv.Itoa(level)
+}
+
+// LoadCategoryTree reads a category hierarchy from disk
+func LoadCategoryTree(path string) (*dto.CategoryTree, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var tree dto.CategoryTree
+	if err := json.Unmarshal(data, &tree); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return &tree, nil
+}
+
+// keywordNode is a hierarchy node below a catalog category, selected by
+// keywords in the product name
+type keywordNode struct {
+	name     string
+	patterns []*regexp.Regexp
+	children []keywordNode
+}
+
+// categoryMatch is the hierarchy path of a catalog category and the keyword
+// nodes that refine it
+type categoryMatch struct {
+	path     []string
+	children []keywordNode
+}
+
+// CategoryMatcher assigns products to the category hierarchy
+type CategoryMatcher struct {
+	byCategory map[string]categoryMatch
+}
+
+// NewCategoryMatcher compiles the keywords of a category hierarchy
+func NewCategoryMatcher(tree *dto.CategoryTree) *CategoryMatcher {
+	matcher := &CategoryMatcher{byCategory: make(map[string]categoryMatch)}
+	var walk func(nodes []dto.CategoryNode, path []string)
+	walk = func(nodes []dto.CategoryNode, path []string) {
+		for _, node := range nodes {
+			nodePath := append(append([]string{}, path...), node.Name)
+			for _, category := range node.Categories {
+				matcher.byCategory[strings.ToLower(category)] = categoryMatch{
+					path:     nodePath,
+					children: newKeywordNodes(node.Children),
+				}
+			}
+			walk(node.Children, nodePath)
+		}
+	}
+	walk(tree.Categories, nil)
+	return matcher
+}
+
+// newKeywordNodes compiles the keywords of hierarchy nodes as whole words
+func newKeywordNodes(nodes []dto.CategoryNode) []keywordNode {
+	var keywordNodes []keywordNode
+	for _, node := range nodes {
+		if len(node.Keywords) == 0 {
+			continue
+		}
+		compiled := keywordNode{name: node.Name, children: newKeywordNodes(node.Children)}
+		for _, keyword := range node.Keywords {
+			words := strings.Fields(regexp.QuoteMeta(strings.ToLower(keyword)))
+			if len(words) > 0 {
+				compiled.patterns = append(compiled.patterns,
+					regexp.MustCompile(`(?i)\b`+strings.Join(words, `\s+`)+`\b`))
+			}
+		}
+		keywordNodes = append(keywordNodes, compiled)
+	}
+	return keywordNodes
+}
+
+// Path returns the hierarchy path of a product. Catalog categories missing
+// from the hierarchy are placed under OtherCategory.
+func (m *CategoryMatcher) Path(name, category string) []string {
+	match, ok := m.byCategory[strings.ToLower(category)]
+	if !ok {
+		if category == "" {
+			return nil
+		}
+		return []string{OtherCategory, category}
+	}
+
+	// Only the first line describes the product; later lines are notes such
+	// as "Add TRACK SET separately"
+	if idx := strings.Index(name, "\n"); idx >= 0 {
+		name = name[:idx]
+	}
+
+	path := append([]string{}, match.path...)
+	children := match.children
+	for len(children) > 0 {
+		next := matchKeywordNode(children, name)
+		if next == nil {
+			break
+		}
+		path = append(path, next.name)
+		children = next.children
+	}
+	return path
+}
+
+// matchKeywordNode returns the first node with a keyword in name
+func matchKeywordNode(nodes []keywordNode, name string) *keywordNode {
+	for i := range nodes {
+		for _, pattern := range nodes[i].patterns {
+			if pattern.MatchString(name) {
+				return &nodes[i]
+			}
+		}
+	}
+	return nil
+}
+
+// CategoryLevelValues converts a hierarchy path into the values of the
+// category level fields, e.g. {"lvl0": "Hardware", "lvl1": "Hardware > Channels"}
+func CategoryLevelValues(path []string) map[string]string {
+	levels := make(map[string]string)
+	for level := 0; level < len(path) && level < CategoryLevels; level++ {
+		levels["lvl"+strconv.Itoa(level)] = strings.Join(path[:level+1], CategoryPathSeparator)
+	}
+	return levels
+}
+
+// categoryFilter matches products within a category path such as
+// "Hardware > Channels"
+func categoryFilter(path string) string {
+	level := strings.Count(path, CategoryPathSeparator)
+	if level >= CategoryLevels {
+		level = CategoryLevels - 1
+	}
+	return categoryLevelField(level) + " = " + quoteFilterValue(path)
+}
+
+// CategoryService serves the category hierarchy with product counts
+type CategoryService struct {
+	index meilisearch.IndexManager
+	path  string
+
+	once    sync.Once
+	tree    *dto.CategoryTree
+	loadErr error
+}
+
+// NewCategoryService creates a category service using the given hierarchy
+// file. The file is loaded on first use.
+func NewCategoryService(client meilisearch.ServiceManager, path string) *CategoryService {
+	return &CategoryService{
+		index: client.Index(ProductIndexUID),
+		path:  path,
+	}
+}
+
+// Categories returns the category hierarchy with the number of active
+// products in each node. Nodes without products are left out.
+func (s *CategoryService) Categories(ctx context.Context) ([]dto.CategorySummary, error) {
+	tree, err := s.loadTree()
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := s.index.SearchWithContext(ctx, "", &meilisearch.SearchRequest{
+		Limit:                1,
+		AttributesToRetrieve: []string{"id"},
+		Filter:               []string{"is_active = 1"},
+		Facets:               CategoryLevelFields,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[string]int64)
+	for _, field := range CategoryLevelFields {
+		for path, count := range facetCounts(result.FacetDistribution, field) {
+			counts[path] = count
+		}
+	}
+
+	summaries := summarizeCategories(tree.Categories, nil, counts)
+	// Catalog categories missing from the hierarchy are grouped under Other
+	if count, ok := counts[OtherCategory]; ok {
+		other := dto.CategorySummary{Name: OtherCategory, Path: OtherCategory, Count: count}
+		prefix := OtherCategory + CategoryPathSeparator
+		for path, count := range counts {
+			if strings.HasPrefix(path, prefix) && strings.Count(path, CategoryPathSeparator) == 1 {
+				other.Children = append(other.Children, dto.CategorySummary{
+					Name:  strings.TrimPrefix(path, prefix),
+					Path:  path,
+					Level: 1,
+					Count: count,
+				})
+			}
+		}
+		sort.Slice(other.Children, func(i, j int) bool {
+			return other.Children[i].Name < other.Children[j].Name
+		})
+		summaries = append(summaries, other)
+	}
+	return summaries, nil
+}
+
+// summarizeCategories attaches product counts to hierarchy nodes
+func summarizeCategories(nodes []dto.CategoryNode, parent []string, counts map[string]int64) []dto.CategorySummary {
+	summaries := []dto.CategorySummary{}
+	if len(parent) >= CategoryLevels {
+		return summaries
+	}
+	for _, node := range nodes {
+		path := append(append([]string{}, parent...), node.Name)
+		joined := strings.Join(path, CategoryPathSeparator)
+		count, ok := counts[joined]
+		if !ok {
+			continue
+		}
+		summary := dto.CategorySummary{
+			Name:     node.Name,
+			Path:     joined,
+			Level:    len(parent),
+			Count:    count,
+			Children: summarizeCategories(node.Children, path, counts),
+		}
+		if len(summary.Children) == 0 {
+			summary.Children = nil
+		}
+		summaries = append(summaries, summary)
+	}
+	return summaries
+}
+
+// ResolvePath matches path segments such as ["hardware", "channels"] against
+// the hierarchy, ignoring case, and returns the category path
+// "Hardware > Channels"
+func (s *CategoryService) ResolvePath(segments []string) (string, error) {
+	tree, err := s.loadTree()
+	if err != nil {
+		return "", err
+	}
+	if len(segments) == 0 || len(segments) > CategoryLevels {
+		return "", ErrCategoryNotFound
+	}
+	if len(segments) <= 2 && strings.EqualFold(segments[0], OtherCategory) {
+		segments[0] = OtherCategory
+		return strings.Join(segments, CategoryPathSeparator), nil
+	}
+
+	var path []string
+	nodes := tree.Categories
+	for _, segment := range segments {
+		var found *dto.CategoryNode
+		for i := range nodes {
+			if strings.EqualFold(nodes[i].Name, strings.TrimSpace(segment)) {
+				found = &nodes[i]
+				break
+			}
+		}
+		if found == nil {
+			return "", ErrCategoryNotFound
+		}
+		path = append(path, found.Name)
+		nodes = found.Children
+	}
+	return strings.Join(path, CategoryPathSeparator), nil
+}
+
+// loadTree loads the category hierarchy once
+func (s *CategoryService) loadTree() (*dto.CategoryTree, error) {
+	s.once.Do(func() {
+		s.tree, s.loadErr = LoadCategoryTree(s.path)
+	})
+	return s.tree, s.loadErr
+}
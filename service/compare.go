@@ -0,0 +1,190 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"meilisearch/dto"
+)
+
+// Limits on the number of products compared side by side
+const (
+	MinCompareProducts = 2
+	MaxCompareProducts = 4
+)
+
+// FieldDiscount holds the catalog discount percentage
+const FieldDiscount = "discount"
+
+// millimetresPerInch converts inch measurements into the millimetre rows
+const millimetresPerInch = 25.4
+
+var (
+	// ErrInvalidComparison is returned for a comparison of too few or too
+	// many products
+	ErrInvalidComparison = errors.New("invalid comparison")
+	// ErrIncompatibleCategories is returned when the compared products come
+	// from different categories
+	ErrIncompatibleCategories = errors.New("products belong to different categories")
+)
+
+// compareRow describes one row of a comparison matrix
+type compareRow struct {
+	field string
+	label string
+	unit  string
+}
+
+// compareRows are the rows of a comparison matrix, in display order
+var compareRows = []compareRow{
+	{FieldBrand, "Brand", ""},
+	{FieldThicknessMM, "Thickness", "mm"},
+	{FieldLengthMM, "Length", "mm"},
+	{FieldHeightMM, "Height", "mm"},
+	{FieldLoadKG, "Load", "kg"},
+	{FieldWeightG, "Pack size", "g"},
+	{FieldVolumeML, "Pack size", "ml"},
+	{FieldSheetSize, "Sheet size", ""},
+	{FieldCrank, "Crank", ""},
+	{FieldCloseType, "Close type", ""},
+	{FieldFinish, "Finish", ""},
+	{FieldPrice, "Price", QuoteCurrency},
+	{FieldDiscount, "Discount", "%"},
+}
+
+// Compare builds a side-by-side attribute matrix of 2 to 4 products from the
+// same category. Rows no product has are left out; the remaining rows are
+// flagged when their values differ.
+func (s *ProductService) Compare(ctx context.Context, ids []int) (*dto.CompareResponse, error) {
+	var unique []int
+	seen := make(map[int]bool)
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+	if len(unique) < MinCompareProducts || len(unique) > MaxCompareProducts {
+		return nil, fmt.Errorf("%w: between %d and %d distinct product IDs are required",
+			ErrInvalidComparison, MinCompareProducts, MaxCompareProducts)
+	}
+
+	values := make([]string, len(unique))
+	for i, id := range unique {
+		values[i] = strconv.Itoa(id)
+	}
+	found, err := fetchProducts(ctx, s.index, "id IN ["+strings.Join(values, ", ")+"]", len(unique))
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[int]dto.Product, len(found))
+	for _, product := range found {
+		byID[product.ID] = product
+	}
+
+	products := make([]dto.Product, len(unique))
+	for i, id := range unique {
+		product, ok := byID[id]
+		if !ok {
+			return nil, fmt.Errorf("%w: %d", ErrProductNotFound, id)
+		}
+		products[i] = product
+	}
+
+	categories := make(map[string]bool)
+	for _, product := range products {
+		categories[product.CategoryName] = true
+	}
+	if len(categories) > 1 {
+		names := make([]string, 0, len(categories))
+		for name := range categories {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return nil, fmt.Errorf("%w: %s", ErrIncompatibleCategories, strings.Join(names, ", "))
+	}
+
+	response := &dto.CompareResponse{
+		CategoryName: products[0].CategoryName,
+		Products:     make([]dto.ComparedProduct, len(products)),
+		Attributes:   []dto.CompareAttribute{},
+		Differing:    []string{},
+	}
+	columns := make([]map[string]interface{}, len(products))
+	for i, product := range products {
+		response.Products[i] = dto.ComparedProduct{
+			ID:           product.ID,
+			SKU:          product.SKU,
+			Name:         product.Name,
+			CategoryName: product.CategoryName,
+			UnitType:     product.UnitType,
+		}
+		if columns[i], err = compareValues(product); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, row := range compareRows {
+		attribute := dto.CompareAttribute{
+			Field:  row.field,
+			Label:  row.label,
+			Unit:   row.unit,
+			Values: make([]interface{}, len(columns)),
+		}
+		present := false
+		for i, column := range columns {
+			if value, ok := column[row.field]; ok {
+				attribute.Values[i] = value
+				present = true
+			}
+			if i > 0 && attributeText(attribute.Values[i]) != attributeText(attribute.Values[0]) {
+				attribute.Differs = true
+			}
+		}
+		if !present {
+			continue
+		}
+		response.Attributes = append(response.Attributes, attribute)
+		if attribute.Differs {
+			response.Differing = append(response.Differing, row.field)
+		}
+	}
+	return response, nil
+}
+
+// compareValues collects the comparable values of a product. Inch sizes are
+// converted to millimetres so they line up with metric products, and the
+// price is the unit price used for quotes.
+func compareValues(product dto.Product) (map[string]interface{}, error) {
+	values, err := variantAttributes(product)
+	if err != nil {
+		return nil, err
+	}
+
+	if product.LengthMM == nil && product.LengthIN != nil {
+		values[FieldLengthMM] = inchesToMillimetres(*product.LengthIN)
+	}
+	if product.HeightMM == nil && product.HeightIN != nil {
+		values[FieldHeightMM] = inchesToMillimetres(*product.HeightIN)
+	}
+	if product.Brand != "" {
+		values[FieldBrand] = product.Brand
+	}
+	if price, _, status := unitPrice(product); status == PriceStatusPriced {
+		values[FieldPrice] = price
+	}
+	if discount, _, ok := parseAmount(product.Discount); ok {
+		values[FieldDiscount] = discount
+	}
+	return values, nil
+}
+
+// inchesToMillimetres converts an inch measurement, rounded to 0.1 mm
+func inchesToMillimetres(inches float64) float64 {
+	return math.Round(inches*millimetresPerInch*10) / 10
+}
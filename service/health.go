@@ -0,0 +1,181 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"strconv"
+	"sync"
+	"time"
+
+	"meilisearch/dto"
+
+	"github.com/meilisearch/meilisearch-go"
+)
+
+// Defaults for the readiness checks
+const (
+	DefaultReadyMinDocuments    = 1
+	DefaultReadyMaxPendingTasks = 100
+	DefaultHealthCacheTTL       = 5 * time.Second
+	DefaultHealthTimeout        = 2 * time.Second
+)
+
+// Names of the readiness checks
+const (
+	CheckMeilisearch = "meilisearch"
+	CheckIndex       = "index"
+	CheckDocuments   = "documents"
+	CheckTasks       = "tasks"
+)
+
+// HealthService probes Meilisearch to decide whether the API can serve
+// search traffic. Results are cached so frequent probes do not load the
+// search engine.
+type HealthService struct {
+	client meilisearch.ServiceManager
+	index  meilisearch.IndexManager
+
+	minDocuments    int64
+	maxPendingTasks int64
+	cacheTTL        time.Duration
+	timeout         time.Duration
+
+	mu     sync.Mutex
+	report *dto.ReadinessReport
+}
+
+// HealthOption configures a HealthService
+type HealthOption func(*HealthService)
+
+// WithReadyMinDocuments sets the number of indexed documents below which the
+// service is not ready
+func WithReadyMinDocuments(count int64) HealthOption {
+	return func(s *HealthService) {
+		s.minDocuments = count
+	}
+}
+
+// WithReadyMaxPendingTasks sets the number of enqueued or processing index
+// tasks above which the service is not ready
+func WithReadyMaxPendingTasks(count int64) HealthOption {
+	return func(s *HealthService) {
+		s.maxPendingTasks = count
+	}
+}
+
+// WithHealthCacheTTL sets how long a readiness report is reused
+func WithHealthCacheTTL(ttl time.Duration) HealthOption {
+	return func(s *HealthService) {
+		s.cacheTTL = ttl
+	}
+}
+
+// WithHealthTimeout sets the time allowed for all readiness checks together
+func WithHealthTimeout(timeout time.Duration) HealthOption {
+	return func(s *HealthService) {
+		s.timeout = timeout
+	}
+}
+
+// NewHealthService creates a new health service
+func NewHealthService(client meilisearch.ServiceManager, opts ...HealthOption) *HealthService {
+	s := &HealthService{
+		client:          client,
+		index:           client.Index(ProductIndexUID),
+		minDocuments:    DefaultReadyMinDocuments,
+		maxPendingTasks: DefaultReadyMaxPendingTasks,
+		cacheTTL:        DefaultHealthCacheTTL,
+		timeout:         DefaultHealthTimeout,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// Readiness reports whether Meilisearch is available, the product index
+// exists with enough documents and its task backlog is short. A report
+// younger than the cache TTL is reused. The checks run without the caller's
+// cancellation, so a client that disconnects does not cache a failure.
+func (s *HealthService) Readiness(ctx context.Context) dto.ReadinessReport {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.report != nil && time.Since(s.report.CheckedAt) < s.cacheTTL {
+		report := *s.report
+		report.Cached = true
+		return report
+	}
+
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), s.timeout)
+	defer cancel()
+
+	report := s.check(ctx)
+	s.report = &report
+	return report
+}
+
+// check runs the readiness checks. Checks that depend on a failed one are
+// reported as failed without being run. Error details are logged rather
+// than reported, as the report is public.
+func (s *HealthService) check(ctx context.Context) dto.ReadinessReport {
+	report := dto.ReadinessReport{Ready: true, CheckedAt: time.Now()}
+	add := func(name string, ok bool, message string) {
+		status := dto.CheckPass
+		if !ok {
+			status = dto.CheckFail
+			report.Ready = false
+		}
+		report.Checks = append(report.Checks, dto.HealthCheckResult{Name: name, Status: status, Message: message})
+	}
+	skip := func(reason string, names ...string) {
+		for _, name := range names {
+			add(name, false, "not checked: "+reason)
+		}
+	}
+
+	health, err := s.client.HealthWithContext(ctx)
+	switch {
+	case err != nil:
+		slog.WarnContext(ctx, "readiness check failed", "check", CheckMeilisearch, "error", err)
+		add(CheckMeilisearch, false, "unreachable")
+		skip("Meilisearch is unavailable", CheckIndex, CheckDocuments, CheckTasks)
+		return report
+	case health.Status != "available":
+		add(CheckMeilisearch, false, "status "+health.Status)
+		skip("Meilisearch is unavailable", CheckIndex, CheckDocuments, CheckTasks)
+		return report
+	}
+	add(CheckMeilisearch, true, "available")
+
+	stats, err := s.index.GetStatsWithContext(ctx)
+	switch {
+	case isNotFound(err):
+		add(CheckIndex, false, "index "+ProductIndexUID+" does not exist")
+		skip("index is missing", CheckDocuments)
+	case err != nil:
+		slog.WarnContext(ctx, "readiness check failed", "check", CheckIndex, "error", err)
+		add(CheckIndex, false, "stats unavailable")
+		skip("index stats are unavailable", CheckDocuments)
+	default:
+		add(CheckIndex, true, "index "+ProductIndexUID+" exists")
+		add(CheckDocuments, stats.NumberOfDocuments >= s.minDocuments,
+			strconv.FormatInt(stats.NumberOfDocuments, 10)+" documents (minimum "+
+				strconv.FormatInt(s.minDocuments, 10)+")")
+	}
+
+	tasks, err := s.client.GetTasksWithContext(ctx, &meilisearch.TasksQuery{
+		IndexUIDS: []string{ProductIndexUID},
+		Statuses:  []meilisearch.TaskStatus{meilisearch.TaskStatusEnqueued, meilisearch.TaskStatusProcessing},
+		Limit:     1,
+	})
+	if err != nil {
+		slog.WarnContext(ctx, "readiness check failed", "check", CheckTasks, "error", err)
+		add(CheckTasks, false, "task queue unavailable")
+		return report
+	}
+	add(CheckTasks, tasks.Total <= s.maxPendingTasks,
+		strconv.FormatInt(tasks.Total, 10)+" pending tasks (maximum "+
+			strconv.FormatInt(s.maxPendingTasks, 10)+")")
+	return report
+}
@@ -0,0 +1,196 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"meilisearch/dto"
+
+	"github.com/meilisearch/meilisearch-go"
+)
+
+// healthClient answers the readiness probes from fixed results
+type healthClient struct {
+	meilisearch.ServiceManager
+	status   string
+	err      error
+	pending  int64
+	tasksErr error
+	index    *statsIndex
+	probes   int
+}
+
+func (c *healthClient) Index(string) meilisearch.IndexManager {
+	return c.index
+}
+
+func (c *healthClient) HealthWithContext(ctx context.Context) (*meilisearch.Health, error) {
+	c.probes++
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if c.err != nil {
+		return nil, c.err
+	}
+	return &meilisearch.Health{Status: c.status}, nil
+}
+
+func (c *healthClient) GetTasksWithContext(context.Context, *meilisearch.TasksQuery) (*meilisearch.TaskResult, error) {
+	if c.tasksErr != nil {
+		return nil, c.tasksErr
+	}
+	return &meilisearch.TaskResult{Total: c.pending}, nil
+}
+
+// statsIndex serves index stats with a fixed document count
+type statsIndex struct {
+	meilisearch.IndexManager
+	documents int64
+	err       error
+}
+
+func (i *statsIndex) GetStatsWithContext(context.Context) (*meilisearch.StatsIndex, error) {
+	if i.err != nil {
+		return nil, i.err
+	}
+	return &meilisearch.StatsIndex{NumberOfDocuments: i.documents}, nil
+}
+
+func TestReadiness(t *testing.T) {
+	available := func(documents, pending int64) *healthClient {
+		return &healthClient{status: "available", pending: pending, index: &statsIndex{documents: documents}}
+	}
+	tests := []struct {
+		name   string
+		client *healthClient
+		ready  bool
+		checks []string
+	}{
+		{
+			name:   "ready",
+			client: available(10, 0),
+			ready:  true,
+			checks: []string{dto.CheckPass, dto.CheckPass, dto.CheckPass, dto.CheckPass},
+		},
+		{
+			name:   "documents at the minimum",
+			client: available(5, 0),
+			ready:  true,
+			checks: []string{dto.CheckPass, dto.CheckPass, dto.CheckPass, dto.CheckPass},
+		},
+		{
+			name:   "documents below the minimum",
+			client: available(4, 0),
+			checks: []string{dto.CheckPass, dto.CheckPass, dto.CheckFail, dto.CheckPass},
+		},
+		{
+			name:   "pending tasks at the maximum",
+			client: available(10, 3),
+			ready:  true,
+			checks: []string{dto.CheckPass, dto.CheckPass, dto.CheckPass, dto.CheckPass},
+		},
+		{
+			name:   "pending tasks above the maximum",
+			client: available(10, 4),
+			checks: []string{dto.CheckPass, dto.CheckPass, dto.CheckPass, dto.CheckFail},
+		},
+		{
+			name:   "task queue unavailable",
+			client: &healthClient{status: "available", tasksErr: errors.New("timeout"), index: &statsIndex{documents: 10}},
+			checks: []string{dto.CheckPass, dto.CheckPass, dto.CheckPass, dto.CheckFail},
+		},
+		{
+			name:   "missing index",
+			client: &healthClient{status: "available", index: &statsIndex{err: &meilisearch.Error{StatusCode: http.StatusNotFound}}},
+			checks: []string{dto.CheckPass, dto.CheckFail, dto.CheckFail, dto.CheckPass},
+		},
+		{
+			name:   "Meilisearch unreachable",
+			client: &healthClient{err: errors.New("connection refused"), index: &statsIndex{}},
+			checks: []string{dto.CheckFail, dto.CheckFail, dto.CheckFail, dto.CheckFail},
+		},
+		{
+			name:   "Meilisearch not available",
+			client: &healthClient{status: "degraded", index: &statsIndex{}},
+			checks: []string{dto.CheckFail, dto.CheckFail, dto.CheckFail, dto.CheckFail},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewHealthService(tt.client, WithReadyMinDocuments(5), WithReadyMaxPendingTasks(3))
+			report := s.Readiness(context.Background())
+
+			if report.Ready != tt.ready {
+				t.Errorf("ready = %v, want %v", report.Ready, tt.ready)
+			}
+			var names, checks []string
+			for _, check := range report.Checks {
+				names = append(names, check.Name)
+				checks = append(checks, check.Status)
+			}
+			if want := []string{CheckMeilisearch, CheckIndex, CheckDocuments, CheckTasks}; !reflect.DeepEqual(names, want) {
+				t.Errorf("checks = %q, want %q", names, want)
+			}
+			if !reflect.DeepEqual(checks, tt.checks) {
+				t.Errorf("statuses = %q, want %q", checks, tt.checks)
+			}
+		})
+	}
+}
+
+func TestReadinessCache(t *testing.T) {
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	tests := []struct {
+		name   string
+		opts   []HealthOption
+		ctx    context.Context
+		probes int
+		cached []bool
+	}{
+		{
+			name:   "reports are reused within the TTL",
+			ctx:    context.Background(),
+			probes: 1,
+			cached: []bool{false, true, true},
+		},
+		{
+			name:   "zero TTL probes every time",
+			opts:   []HealthOption{WithHealthCacheTTL(0)},
+			ctx:    context.Background(),
+			probes: 3,
+			cached: []bool{false, false, false},
+		},
+		{
+			name:   "canceled callers still run the checks",
+			ctx:    canceled,
+			probes: 1,
+			cached: []bool{false, true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &healthClient{status: "available", index: &statsIndex{documents: 1}}
+			s := NewHealthService(client, tt.opts...)
+
+			for i, want := range tt.cached {
+				report := s.Readiness(tt.ctx)
+				if report.Cached != want {
+					t.Errorf("report %d cached = %v, want %v", i+1, report.Cached, want)
+				}
+				if !report.Ready {
+					t.Errorf("report %d is not ready: %+v", i+1, report.Checks)
+				}
+			}
+			if client.probes != tt.probes {
+				t.Errorf("probed %d times, want %d", client.probes, tt.probes)
+			}
+		})
+	}
+}
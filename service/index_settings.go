@@ -0,0 +1,94 @@
+package service
+
+import (
+	"context"
+
+	"github.com/meilisearch/meilisearch-go"
+)
+
+// FilterableAttributes lists the product fields search filters may reference
+var FilterableAttributes = append([]string{
+	"id",
+	"sku",
+	"is_active",
+	"status",
+	FieldSoftDeleted,
+	"category_id",
+	"category_name",
+	FieldBrand,
+	FieldVariantGroup,
+}, append(CategoryLevelFields, AttributeFields...)...)
+
+// FieldPrice is the numeric selling price added to product documents at index
+// time, as the catalog stores prices as strings
+const FieldPrice = "price"
+
+// SortableAttributes lists the product fields results may be sorted by
+var SortableAttributes = []string{"name", FieldPrice, "created_at", "updated_at"}
+
+// Sort orders accepted alongside a sortable attribute
+const (
+	SortAsc  = "asc"
+	SortDesc = "desc"
+)
+
+// ValidSort reports whether a sort attribute and order are accepted. An empty
+// attribute means relevance order; an empty order means ascending.
+func ValidSort(sortBy, sortOrder string) bool {
+	if sortOrder != "" && sortOrder != SortAsc && sortOrder != SortDesc {
+		return false
+	}
+	if sortBy == "" {
+		return true
+	}
+	for _, attribute := range SortableAttributes {
+		if sortBy == attribute {
+			return true
+		}
+	}
+	return false
+}
+
+// sortExpression converts a sort attribute and order into a Meilisearch sort
+// rule, or "" for relevance order and unsupported attributes
+func sortExpression(sortBy, sortOrder string) string {
+	if sortBy == "" || !ValidSort(sortBy, sortOrder) {
+		return ""
+	}
+	if sortOrder == "" {
+		sortOrder = SortAsc
+	}
+	return sortBy + ":" + sortOrder
+}
+
+// allowedFacets drops requested facets that are not filterable attributes
+func allowedFacets(facets []string) []string {
+	var allowed []string
+	for _, facet := range facets {
+		for _, attribute := range FilterableAttributes {
+			if facet == attribute {
+				allowed = append(allowed, facet)
+				break
+			}
+		}
+	}
+	return allowed
+}
+
+// MaxTotalHits bounds exhaustive hit counts; it is kept above the catalog size
+// so page-based pagination reports exact totals
+const MaxTotalHits = 10000
+
+// ProductIndexSettings returns the Meilisearch settings for the product index
+func ProductIndexSettings() *meilisearch.Settings {
+	return &meilisearch.Settings{
+		FilterableAttributes: FilterableAttributes,
+		SortableAttributes:   SortableAttributes,
+		Pagination:           &meilisearch.Pagination{MaxTotalHits: MaxTotalHits},
+	}
+}
+
+// ApplyIndexSettings pushes the product index settings to Meilisearch
+func ApplyIndexSettings(ctx context.Context, index meilisearch.IndexManager) (*meilisearch.TaskInfo, error) {
+	return index.UpdateSettingsWithContext(ctx, ProductIndexSettings())
+}
@@ -0,0 +1,236 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"meilisearch/dto"
+)
+
+// Limits and thresholds applied when matching a pasted material list
+const (
+	// MaxMatchListLines caps the number of lines matched in one request
+	MaxMatchListLines = MaxMultiSearchQueries
+	// DefaultMatchAlternatives is the number of alternatives listed per line
+	DefaultMatchAlternatives = 3
+	// MaxMatchAlternatives caps the number of alternatives listed per line
+	MaxMatchAlternatives = 10
+	// MatchConfidenceThreshold is the confidence below which a line is
+	// flagged for review
+	MatchConfidenceThreshold = 0.75
+	// matchAmbiguityMargin is the score difference under which the best two
+	// candidates are considered a tie
+	matchAmbiguityMargin = 0.02
+)
+
+// Reasons a matched line is flagged for human review
+const (
+	ReviewNoMatch       = "no matching product"
+	ReviewLowConfidence = "low confidence"
+	ReviewSizeNotFound  = "requested size not found"
+	ReviewAmbiguous     = "several products match equally well"
+)
+
+var (
+	// ErrEmptyMatchList is returned for a material list without any lines
+	ErrEmptyMatchList = errors.New("material list is empty")
+	// ErrTooManyLines is returned for a material list above MaxMatchListLines
+	ErrTooManyLines = errors.New("material list has too many lines")
+)
+
+var (
+	// listBulletPattern finds list markers such as "- ", "* " or "3. "
+	listBulletPattern = regexp.MustCompile(`^\s*(?:[-*•]|\d+[.)])\s+`)
+	// trailingQuantityPattern finds a quantity at the end of a line, as in
+	// "Fevicol SH 5kg x 4", "hinge - 20 nos" or "ply qty: 10"
+	trailingQuantityPattern = regexp.MustCompile(`(?i)^(.*?\S)\s+([x×*]|qty[:.]?|-)\s*(\d+)\s*(?:pcs?|nos?|units?|sheets?|box(?:es)?|packets?|pkts?)?\.?$`)
+	// trailingCountPattern finds a count with a unit at the end of a line, as
+	// in "hinge 0 crank 20 nos"
+	trailingCountPattern = regexp.MustCompile(`(?i)^(.*?\S)\s+(\d+)\s*(?:pcs?|nos?|units?|sheets?|box(?:es)?|packets?|pkts?)\.?$`)
+	// leadingQuantityPattern finds a quantity at the start of a line, as in
+	// "4 x Fevicol SH 5kg" or "10 sheets 18mm ply"
+	leadingQuantityPattern = regexp.MustCompile(`(?i)^(\d+)\s*([x×*]|pcs?|nos?|units?|sheets?|box(?:es)?|packets?|pkts?)\s+(.+)$`)
+	// trailingDigitPattern finds a single digit ending a line, the first half
+	// of a sheet size such as "8 x 4"
+	trailingDigitPattern = regexp.MustCompile(`(?:^|\s)\d$`)
+	// leadingDigitPattern finds a single digit starting a line
+	leadingDigitPattern = regexp.MustCompile(`^\d(?:\s|$)`)
+)
+
+// MatchList matches each line of a pasted material list to the best product.
+// Quantities such as "x 4" or "20 nos" are split off the line, the rest is
+// searched, and lines with weak, ambiguous or size-less matches are flagged
+// for review.
+func (s *ProductService) MatchList(ctx context.Context, req dto.MatchListRequest) (*dto.MatchListResponse, error) {
+	lines := req.Lines
+	if len(lines) == 0 {
+		lines = strings.Split(req.Text, "\n")
+	}
+
+	alternatives := req.Alternatives
+	if alternatives <= 0 {
+		alternatives = DefaultMatchAlternatives
+	}
+	if alternatives > MaxMatchAlternatives {
+		alternatives = MaxMatchAlternatives
+	}
+
+	var (
+		results  []dto.MatchListLine
+		searches []dto.ProductSearchRequest
+	)
+	for i, line := range lines {
+		input := strings.TrimSpace(line)
+		if input == "" {
+			continue
+		}
+		query, quantity, found := splitQuantity(listBulletPattern.ReplaceAllString(input, ""))
+		results = append(results, dto.MatchListLine{
+			Line:          i + 1,
+			Input:         input,
+			Query:         query,
+			Quantity:      quantity,
+			QuantityFound: found,
+			Alternatives:  []dto.MatchCandidate{},
+			ReviewReasons: []string{},
+		})
+		searches = append(searches, dto.ProductSearchRequest{
+			Query:            query,
+			Limit:            alternatives + 1,
+			Category:         req.Category,
+			ShowRankingScore: true,
+		})
+	}
+	if len(results) == 0 {
+		return nil, ErrEmptyMatchList
+	}
+	if len(results) > MaxMatchListLines {
+		return nil, ErrTooManyLines
+	}
+
+	searchResults, err := s.MultiSearch(ctx, searches)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &dto.MatchListResponse{Lines: results}
+	for i := range results {
+		line := &results[i]
+		result := searchResults.Results[i]
+		if !result.Success {
+			line.Error = result.Error
+			line.NeedsReview = true
+			line.ReviewReasons = append(line.ReviewReasons, ReviewNoMatch)
+			response.NeedsReview++
+			continue
+		}
+
+		scoreLine(line, result.Result)
+		if line.Match != nil {
+			response.Matched++
+		}
+		if line.NeedsReview {
+			response.NeedsReview++
+		}
+	}
+	return response, nil
+}
+
+// scoreLine fills in the match, alternatives, confidence and review flags of
+// a material list line from its search result
+func scoreLine(line *dto.MatchListLine, result *dto.ProductSearchResponse) {
+	line.ParsedQuery = result.ParsedQuery
+
+	for i, hit := range result.Hits {
+		candidate := dto.MatchCandidate{
+			ID:           hit.ID,
+			SKU:          hit.SKU,
+			Name:         hit.Name,
+			CategoryName: hit.CategoryName,
+			Brand:        hit.Brand,
+			SellingPrice: hit.SellingPrice,
+			Score:        math.Round(hit.RankingScore*1000) / 1000,
+		}
+		if i == 0 {
+			line.Match = &candidate
+			continue
+		}
+		line.Alternatives = append(line.Alternatives, candidate)
+	}
+
+	if line.Match == nil {
+		line.NeedsReview = true
+		line.ReviewReasons = append(line.ReviewReasons, ReviewNoMatch)
+		return
+	}
+
+	line.Confidence = line.Match.Score
+	if line.Confidence < MatchConfidenceThreshold {
+		line.ReviewReasons = append(line.ReviewReasons, ReviewLowConfidence)
+	}
+	// The search dropped the size filters, so the match is another size
+	if parsed := result.ParsedQuery; parsed != nil && len(parsed.Measurements) > 0 && !parsed.Applied {
+		line.ReviewReasons = append(line.ReviewReasons, ReviewSizeNotFound)
+	}
+	if len(line.Alternatives) > 0 && line.Match.Score-line.Alternatives[0].Score < matchAmbiguityMargin {
+		line.ReviewReasons = append(line.ReviewReasons, ReviewAmbiguous)
+	}
+	line.NeedsReview = len(line.ReviewReasons) > 0
+}
+
+// splitQuantity splits a quantity off a material list line, defaulting to 1.
+// A single digit on both sides of an "x" is a sheet size ("ply 8 x 4"), not a
+// quantity.
+func splitQuantity(line string) (string, int, bool) {
+	line = strings.TrimSpace(whitespacePattern.ReplaceAllString(line, " "))
+
+	if match := trailingQuantityPattern.FindStringSubmatch(line); match != nil {
+		if !isSheetSize(match[1], match[2], match[3], true) {
+			if quantity, ok := parseQuantity(match[3]); ok {
+				return match[1], quantity, true
+			}
+		}
+	}
+	if match := trailingCountPattern.FindStringSubmatch(line); match != nil {
+		if quantity, ok := parseQuantity(match[2]); ok {
+			return match[1], quantity, true
+		}
+	}
+	if match := leadingQuantityPattern.FindStringSubmatch(line); match != nil {
+		if !isSheetSize(match[3], match[2], match[1], false) {
+			if quantity, ok := parseQuantity(match[1]); ok {
+				return match[3], quantity, true
+			}
+		}
+	}
+	return line, 1, false
+}
+
+// isSheetSize reports whether a quantity split at an "x" would cut a sheet
+// size such as "8 x 4" in half. rest is the text on the other side of the
+// separator, before it when trailing is set.
+func isSheetSize(rest, separator, quantity string, trailing bool) bool {
+	if !strings.EqualFold(separator, "x") && separator != "×" && separator != "*" {
+		return false
+	}
+	if len(quantity) != 1 {
+		return false
+	}
+	if trailing {
+		return trailingDigitPattern.MatchString(rest)
+	}
+	return leadingDigitPattern.MatchString(rest)
+}
+
+// parseQuantity parses a positive quantity
+func parseQuantity(value string) (int, bool) {
+	quantity, err := strconv.Atoi(value)
+	if err != nil || quantity <= 0 {
+		return 0, false
+	}
+	return quantity, true
+}
@@ -0,0 +1,37 @@
+package service
+
+import "testing"
+
+func TestSplitQuantity(t *testing.T) {
+	tests := []struct {
+		line     string
+		text     string
+		quantity int
+		found    bool
+	}{
+		{"Fevicol SH 5kg x 4", "Fevicol SH 5kg", 4, true},
+		{"Fevicol SH 5kg X 4", "Fevicol SH 5kg", 4, true},
+		{"ply × 12", "ply", 12, true},
+		{"hinge - 20 nos", "hinge", 20, true},
+		{"ply qty: 10", "ply", 10, true},
+		{"hinge 0 crank 20 nos", "hinge 0 crank", 20, true},
+		{"channel 450mm 6 pcs.", "channel 450mm", 6, true},
+		{"4 x Fevicol SH 5kg", "Fevicol SH 5kg", 4, true},
+		{"10 sheets 18mm ply", "18mm ply", 10, true},
+		{"18mm ply 8 x 4", "18mm ply 8 x 4", 1, false},
+		{"18mm ply 8 x 4 x 10", "18mm ply 8 x 4", 10, true},
+		{"8 x 4 ply", "8 x 4 ply", 1, false},
+		{"hinge x 0", "hinge x 0", 1, false},
+		{"  Fevicol   SH  ", "Fevicol SH", 1, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.line, func(t *testing.T) {
+			text, quantity, found := splitQuantity(tt.line)
+			if text != tt.text || quantity != tt.quantity || found != tt.found {
+				t.Errorf("splitQuantity(%q) = %q, %d, %v; want %q, %d, %v",
+					tt.line, text, quantity, found, tt.text, tt.quantity, tt.found)
+			}
+		})
+	}
+}
@@ -0,0 +1,109 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+
+	"meilisearch/dto"
+
+	"github.com/meilisearch/meilisearch-go"
+)
+
+// MaxMultiSearchQueries caps the number of queries accepted in one multi-search
+const MaxMultiSearchQueries = 50
+
+// ErrMissingQuery is returned for a search without a query string
+var ErrMissingQuery = errors.New("query is required")
+
+// MultiSearch executes a batch of product searches through the Meilisearch
+// multi-search API and returns the results in request order. A failing query
+// is reported in its own result instead of failing the batch.
+func (s *ProductService) MultiSearch(ctx context.Context, queries []dto.ProductSearchRequest) (*dto.MultiSearchResponse, error) {
+	results := make([]dto.MultiSearchResult, len(queries))
+
+	var (
+		normalized []dto.ProductSearchRequest
+		requests   []*meilisearch.SearchRequest
+		parsed     []*dto.ParsedQuery
+		positions  []int
+	)
+	for i, query := range queries {
+		results[i].Index = i
+		if strings.TrimSpace(query.Query) == "" {
+			results[i].Error = ErrMissingQuery.Error()
+			results[i].Code = "MISSING_QUERY"
+			continue
+		}
+
+		req, searchRequest, parsedQuery := s.buildSearchRequest(query)
+		searchRequest.IndexUID = ProductIndexUID
+
+		normalized = append(normalized, req)
+		requests = append(requests, searchRequest)
+		parsed = append(parsed, parsedQuery)
+		positions = append(positions, i)
+	}
+
+	if len(requests) > 0 {
+		res, err := s.client.MultiSearchWithContext(ctx, &meilisearch.MultiSearchRequest{Queries: requests})
+		switch {
+		case err == nil && len(res.Results) == len(requests):
+			for j, pos := range positions {
+				if needsParseFallback(parsed[j], &res.Results[j]) {
+					searchRes, err := s.searchWithoutParsing(ctx, normalized[j], parsed[j])
+					setMultiSearchResult(&results[pos], searchRes, err)
+					continue
+				}
+				searchRes, err := s.finishSearchResponse(ctx, normalized[j], parsed[j], &res.Results[j])
+				setMultiSearchResult(&results[pos], searchRes, err)
+			}
+		case err == nil || isRequestError(err):
+			// Meilisearch rejects the whole batch when a single query is
+			// invalid, so run the queries one by one to isolate the failure
+			for _, pos := range positions {
+				searchRes, err := s.Search(ctx, queries[pos])
+				setMultiSearchResult(&results[pos], searchRes, err)
+			}
+		default:
+			return nil, err
+		}
+	}
+
+	response := &dto.MultiSearchResponse{Results: results}
+	for _, result := range results {
+		if result.Success {
+			response.Succeeded++
+		} else {
+			response.Failed++
+		}
+	}
+	return response, nil
+}
+
+// setMultiSearchResult records the outcome of one query in a multi-search
+func setMultiSearchResult(target *dto.MultiSearchResult, searchRes *dto.ProductSearchResponse, err error) {
+	if err == nil {
+		target.Success = true
+		target.Result = searchRes
+		return
+	}
+
+	target.Error = "Search operation failed"
+	target.Code = "SEARCH_FAILED"
+	var meiliErr *meilisearch.Error
+	if errors.As(err, &meiliErr) && meiliErr.MeilisearchApiError.Message != "" {
+		target.Error = meiliErr.MeilisearchApiError.Message
+	}
+}
+
+// isRequestError reports whether Meilisearch rejected a request as invalid,
+// as opposed to being unreachable or failing internally
+func isRequestError(err error) bool {
+	var meiliErr *meilisearch.Error
+	if errors.As(err, &meiliErr) {
+		return meiliErr.StatusCode >= http.StatusBadRequest && meiliErr.StatusCode < http.StatusInternalServerError
+	}
+	return false
+}
@@ -0,0 +1,438 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"meilisearch/dto"
+
+	"github.com/meilisearch/meilisearch-go"
+)
+
+// ProductIndexUID is the Meilisearch index holding the product catalog
+const ProductIndexUID = "sku"
+
+// Product status values stored in the catalog
+const (
+	StatusActive   = "Active"
+	StatusInactive = "Inactive"
+)
+
+// Page size limits applied to product searches
+const (
+	DefaultPerPage = 20
+	MaxPerPage     = 100
+)
+
+// ErrProductNotFound is returned when no document exists for a product ID
+var ErrProductNotFound = errors.New("product not found")
+
+// ProductService wraps the Meilisearch product index
+type ProductService struct {
+	client  meilisearch.ServiceManager
+	index   meilisearch.IndexManager
+	suggest SuggestConfig
+	parser  *QueryParser
+}
+
+// ProductOption configures a ProductService
+type ProductOption func(*ProductService)
+
+// WithUnitTolerance sets the relative tolerance used when matching sizes
+// across inches and millimetres
+func WithUnitTolerance(tolerance float64) ProductOption {
+	return func(s *ProductService) {
+		s.parser = NewQueryParser(tolerance)
+	}
+}
+
+// WithSuggestConfig tunes search-as-you-type. Zero fields keep their
+// defaults.
+func WithSuggestConfig(cfg SuggestConfig) ProductOption {
+	return func(s *ProductService) {
+		s.suggest = mergeSuggestConfig(DefaultSuggestConfig(), cfg)
+	}
+}
+
+// NewProductService creates a new product service
+func NewProductService(client meilisearch.ServiceManager, opts ...ProductOption) *ProductService {
+	s := &ProductService{
+		client:  client,
+		index:   client.Index(ProductIndexUID),
+		suggest: DefaultSuggestConfig(),
+		parser:  NewQueryParser(DefaultUnitTolerance),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// Search runs a product search, excluding inactive products unless requested.
+// Setting req.Page switches Meilisearch to exhaustive page-based pagination so
+// the response carries exact hit and page totals. With req.CollapseVariants
+// only the best-ranked product of each variant group is returned.
+func (s *ProductService) Search(ctx context.Context, req dto.ProductSearchRequest) (*dto.ProductSearchResponse, error) {
+	req, searchRequest, parsed := s.buildSearchRequest(req)
+
+	result, err := s.index.SearchWithContext(ctx, searchRequest.Query, searchRequest)
+	if err != nil {
+		return nil, err
+	}
+	if needsParseFallback(parsed, result) {
+		return s.searchWithoutParsing(ctx, req, parsed)
+	}
+	return s.finishSearchResponse(ctx, req, parsed, result)
+}
+
+// searchWithoutParsing repeats a search with the original query text when
+// the attribute filters derived from it matched nothing
+func (s *ProductService) searchWithoutParsing(ctx context.Context, req dto.ProductSearchRequest, parsed *dto.ParsedQuery) (*dto.ProductSearchResponse, error) {
+	req.RawQuery = true
+	req, searchRequest, _ := s.buildSearchRequest(req)
+
+	result, err := s.index.SearchWithContext(ctx, searchRequest.Query, searchRequest)
+	if err != nil {
+		return nil, err
+	}
+	parsed.Applied = false
+	return s.finishSearchResponse(ctx, req, parsed, result)
+}
+
+// finishSearchResponse converts a search result and, for collapsed searches,
+// adds the variant count of each hit
+func (s *ProductService) finishSearchResponse(ctx context.Context, req dto.ProductSearchRequest, parsed *dto.ParsedQuery, result *meilisearch.SearchResponse) (*dto.ProductSearchResponse, error) {
+	response, err := newSearchResponse(req, parsed, result)
+	if err != nil {
+		return nil, err
+	}
+	if req.CollapseVariants {
+		if err := s.addVariantCounts(ctx, response.Hits, req.IncludeInactive); err != nil {
+			return nil, err
+		}
+	}
+	return response, nil
+}
+
+// buildSearchRequest normalizes a product search request and converts it into
+// a Meilisearch search request. Unless req.RawQuery is set, measurements in the
+// query are turned into attribute filters and the rest is sent as text.
+func (s *ProductService) buildSearchRequest(req dto.ProductSearchRequest) (dto.ProductSearchRequest, *meilisearch.SearchRequest, *dto.ParsedQuery) {
+	req.Limit = clampPageSize(req.Limit)
+	searchRequest := &meilisearch.SearchRequest{
+		Limit:  int64(req.Limit),
+		Offset: int64(req.Offset),
+	}
+	if req.Page > 0 {
+		req.PerPage = clampPageSize(req.PerPage)
+		req.Limit = req.PerPage
+		req.Offset = (req.Page - 1) * req.PerPage
+		searchRequest = &meilisearch.SearchRequest{
+			HitsPerPage: int64(req.PerPage),
+			Page:        int64(req.Page),
+		}
+	}
+
+	searchRequest.Query = req.Query
+	filter := buildFilter(req)
+
+	var parsed *dto.ParsedQuery
+	if !req.RawQuery {
+		parsed = s.parser.Parse(req.Query)
+		searchRequest.Query = parsed.Text
+		filter = append(filter, queryFilters(parsed)...)
+	}
+
+	if len(filter) > 0 {
+		searchRequest.Filter = filter
+	}
+	req.Facets = allowedFacets(req.Facets)
+	searchRequest.Facets = req.Facets
+	if req.CollapseVariants {
+		searchRequest.Distinct = FieldVariantGroup
+	}
+	searchRequest.ShowRankingScore = req.ShowRankingScore
+	if sort := sortExpression(req.SortBy, req.SortOrder); sort != "" {
+		searchRequest.Sort = []string{sort}
+	}
+	applyFormatting(searchRequest, req)
+	return req, searchRequest, parsed
+}
+
+// needsParseFallback reports whether parsed attribute filters emptied a
+// search. It looks at the total rather than the page of hits, so paging past
+// the last result does not drop the filters. Page searches report TotalHits
+// and offset searches EstimatedTotalHits.
+func needsParseFallback(parsed *dto.ParsedQuery, result *meilisearch.SearchResponse) bool {
+	if parsed == nil || len(parsed.Measurements) == 0 {
+		return false
+	}
+	return result.TotalHits == 0 && result.EstimatedTotalHits == 0
+}
+
+// newSearchResponse converts a Meilisearch search result into our response format
+func newSearchResponse(req dto.ProductSearchRequest, parsed *dto.ParsedQuery, result *meilisearch.SearchResponse) (*dto.ProductSearchResponse, error) {
+	hits, err := decodeHits(result.Hits)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &dto.ProductSearchResponse{
+		Hits:        hits,
+		TotalHits:   int(result.EstimatedTotalHits),
+		Processing:  false,
+		Query:       req.Query,
+		Limit:       req.Limit,
+		Offset:      req.Offset,
+		ParsedQuery: parsed,
+	}
+	if len(req.Facets) > 0 {
+		response.Facets = make(map[string]map[string]int64, len(req.Facets))
+		for _, facet := range req.Facets {
+			response.Facets[facet] = facetCounts(result.FacetDistribution, facet)
+		}
+	}
+	if req.Page > 0 {
+		response.TotalHits = int(result.TotalHits)
+		response.Page = int(result.Page)
+		response.TotalPages = int(result.TotalPages)
+	}
+	return response, nil
+}
+
+// fetchProducts returns up to limit product documents matching a filter
+func fetchProducts(ctx context.Context, index meilisearch.IndexManager, filter string, limit int) ([]dto.Product, error) {
+	var result meilisearch.DocumentsResult
+	err := index.GetDocumentsWithContext(ctx, &meilisearch.DocumentsQuery{
+		Limit:  int64(limit),
+		Filter: filter,
+	}, &result)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := json.Marshal(result.Results)
+	if err != nil {
+		return nil, err
+	}
+	var products []dto.Product
+	if err := json.Unmarshal(data, &products); err != nil {
+		return nil, fmt.Errorf("failed to decode products: %w", err)
+	}
+	return products, nil
+}
+
+// GetProduct fetches a single product document by ID
+func (s *ProductService) GetProduct(ctx context.Context, id int) (*dto.Product, error) {
+	var product dto.Product
+	if err := s.index.GetDocumentWithContext(ctx, strconv.Itoa(id), nil, &product); err != nil {
+		if isNotFound(err) {
+			return nil, ErrProductNotFound
+		}
+		return nil, err
+	}
+	return &product, nil
+}
+
+// SoftDelete marks a product inactive without removing it from the index
+func (s *ProductService) SoftDelete(ctx context.Context, id int) (*dto.TaskStatus, error) {
+	return s.setActive(ctx, id, false)
+}
+
+// Restore marks a previously soft-deleted product active again
+func (s *ProductService) Restore(ctx context.Context, id int) (*dto.TaskStatus, error) {
+	return s.setActive(ctx, id, true)
+}
+
+// Delete permanently removes a product document from the index
+func (s *ProductService) Delete(ctx context.Context, id int) (*dto.TaskStatus, error) {
+	if _, err := s.GetProduct(ctx, id); err != nil {
+		return nil, err
+	}
+
+	task, err := s.index.DeleteDocumentWithContext(ctx, strconv.Itoa(id))
+	if err != nil {
+		return nil, err
+	}
+	return newTaskStatus(task), nil
+}
+
+// GetStats returns document statistics for the product index
+func (s *ProductService) GetStats(ctx context.Context) (*dto.IndexStats, error) {
+	stats, err := s.index.GetStatsWithContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &dto.IndexStats{
+		NumberOfDocuments: stats.NumberOfDocuments,
+		IsIndexing:        stats.IsIndexing,
+	}, nil
+}
+
+// setActive applies a partial update to the is_active and status fields and
+// records the soft delete so the indexer keeps it
+func (s *ProductService) setActive(ctx context.Context, id int, active bool) (*dto.TaskStatus, error) {
+	if _, err := s.GetProduct(ctx, id); err != nil {
+		return nil, err
+	}
+
+	update := map[string]interface{}{
+		"id":             id,
+		"is_active":      0,
+		"status":         StatusInactive,
+		FieldSoftDeleted: 1,
+	}
+	if active {
+		update["is_active"] = 1
+		update["status"] = StatusActive
+		update[FieldSoftDeleted] = 0
+	}
+
+	task, err := s.index.UpdateDocumentsWithContext(ctx, []map[string]interface{}{update}, "id")
+	if err != nil {
+		return nil, fmt.Errorf("failed to update product %d: %w", id, err)
+	}
+	return newTaskStatus(task), nil
+}
+
+// clampPageSize applies the default and maximum page sizes
+func clampPageSize(size int) int {
+	if size <= 0 {
+		return DefaultPerPage
+	}
+	if size > MaxPerPage {
+		return MaxPerPage
+	}
+	return size
+}
+
+// buildFilter converts a search request into Meilisearch filter expressions
+func buildFilter(req dto.ProductSearchRequest) []string {
+	var filter []string
+	if !req.IncludeInactive {
+		filter = append(filter, "is_active = 1")
+	}
+	if req.Category != "" {
+		filter = append(filter, "category_name = "+quoteFilterValue(req.Category))
+	}
+	if req.CategoryPath != "" {
+		filter = append(filter, categoryFilter(req.CategoryPath))
+	}
+	if req.Status != "" {
+		filter = append(filter, "status = "+quoteFilterValue(req.Status))
+	}
+	if brands := splitList(req.Brand); len(brands) > 0 {
+		filter = append(filter, FieldBrand+" IN ["+quoteFilterValues(brands)+"]")
+	}
+	return filter
+}
+
+// splitList splits a comma-separated list, dropping empty entries
+func splitList(value string) []string {
+	var values []string
+	for _, v := range strings.Split(value, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			values = append(values, v)
+		}
+	}
+	return values
+}
+
+// quoteFilterValue quotes a string for use in a Meilisearch filter expression
+func quoteFilterValue(value string) string {
+	return `"` + strings.ReplaceAll(value, `"`, `\"`) + `"`
+}
+
+// applyFormatting maps highlight and crop options onto a search request.
+// Match positions are returned whenever highlighting or cropping is requested.
+func applyFormatting(searchRequest *meilisearch.SearchRequest, req dto.ProductSearchRequest) {
+	if len(req.Highlight) == 0 && len(req.Crop) == 0 {
+		return
+	}
+
+	searchRequest.AttributesToHighlight = req.Highlight
+	searchRequest.AttributesToCrop = req.Crop
+	searchRequest.CropLength = int64(req.CropLength)
+	searchRequest.CropMarker = req.CropMarker
+	searchRequest.HighlightPreTag = req.HighlightPreTag
+	searchRequest.HighlightPostTag = req.HighlightPostTag
+	searchRequest.ShowMatchesPosition = true
+}
+
+// searchHit is a raw Meilisearch hit including its formatting metadata
+type searchHit struct {
+	dto.Product
+	Formatted       map[string]interface{}         `json:"_formatted"`
+	MatchesPosition map[string][]dto.MatchPosition `json:"_matchesPosition"`
+	RankingScore    float64                        `json:"_rankingScore"`
+}
+
+// decodeHits converts raw Meilisearch hits into product hits
+func decodeHits(raw []interface{}) ([]dto.ProductHit, error) {
+	hits := []dto.ProductHit{}
+	if len(raw) == 0 {
+		return hits, nil
+	}
+
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	var decoded []searchHit
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return nil, fmt.Errorf("failed to decode search hits: %w", err)
+	}
+	for _, hit := range decoded {
+		hits = append(hits, dto.ProductHit{
+			Product:         hit.Product,
+			Formatted:       hit.Formatted,
+			MatchesPosition: hit.MatchesPosition,
+			RankingScore:    hit.RankingScore,
+		})
+	}
+	return hits, nil
+}
+
+// facetCounts extracts the value counts of one facet from a Meilisearch
+// facet distribution
+func facetCounts(distribution interface{}, facet string) map[string]int64 {
+	counts := make(map[string]int64)
+
+	facets, ok := distribution.(map[string]interface{})
+	if !ok {
+		return counts
+	}
+	values, ok := facets[facet].(map[string]interface{})
+	if !ok {
+		return counts
+	}
+	for value, count := range values {
+		if n, ok := count.(float64); ok && n > 0 {
+			counts[value] = int64(n)
+		}
+	}
+	return counts
+}
+
+// newTaskStatus converts a Meilisearch task into our response format
+func newTaskStatus(task *meilisearch.TaskInfo) *dto.TaskStatus {
+	return &dto.TaskStatus{
+		TaskUID: task.TaskUID,
+		Status:  string(task.Status),
+	}
+}
+
+// isNotFound reports whether a Meilisearch error is a 404
+func isNotFound(err error) bool {
+	var meiliErr *meilisearch.Error
+	if errors.As(err, &meiliErr) {
+		return meiliErr.StatusCode == http.StatusNotFound
+	}
+	return false
+}
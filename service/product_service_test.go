@@ -0,0 +1,154 @@
+package service
+
+import (
+	"reflect"
+	"testing"
+
+	"meilisearch/dto"
+
+	"github.com/meilisearch/meilisearch-go"
+)
+
+func TestNeedsParseFallback(t *testing.T) {
+	measured := &dto.ParsedQuery{Measurements: []dto.QueryMeasurement{{Raw: "18mm", Unit: "mm"}}}
+	unmeasured := &dto.ParsedQuery{Text: "hinge", Measurements: []dto.QueryMeasurement{}}
+
+	tests := []struct {
+		name   string
+		parsed *dto.ParsedQuery
+		result meilisearch.SearchResponse
+		want   bool
+	}{
+		{
+			name:   "raw query",
+			parsed: nil,
+			want:   false,
+		},
+		{
+			name:   "no measurements",
+			parsed: unmeasured,
+			want:   false,
+		},
+		{
+			name:   "no hits in total",
+			parsed: measured,
+			want:   true,
+		},
+		{
+			name:   "offset past the last hit",
+			parsed: measured,
+			result: meilisearch.SearchResponse{EstimatedTotalHits: 12},
+			want:   false,
+		},
+		{
+			name:   "page past the last page",
+			parsed: measured,
+			result: meilisearch.SearchResponse{TotalHits: 12, Page: 5, TotalPages: 1},
+			want:   false,
+		},
+		{
+			name:   "hits on the page",
+			parsed: measured,
+			result: meilisearch.SearchResponse{Hits: []interface{}{map[string]interface{}{"id": 1}}, EstimatedTotalHits: 1},
+			want:   false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := needsParseFallback(tt.parsed, &tt.result); got != tt.want {
+				t.Errorf("needsParseFallback() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuildSearchRequest(t *testing.T) {
+	s := &ProductService{parser: NewQueryParser(DefaultUnitTolerance)}
+	tests := []struct {
+		name   string
+		req    dto.ProductSearchRequest
+		want   *meilisearch.SearchRequest
+		limit  int
+		offset int
+	}{
+		{
+			name:  "default limit",
+			req:   dto.ProductSearchRequest{Query: "hinge", RawQuery: true},
+			want:  &meilisearch.SearchRequest{Query: "hinge", Limit: DefaultPerPage, Filter: []string{"is_active = 1"}},
+			limit: DefaultPerPage,
+		},
+		{
+			name:   "limit capped at the maximum",
+			req:    dto.ProductSearchRequest{Query: "hinge", Limit: 500, Offset: 40, RawQuery: true},
+			want:   &meilisearch.SearchRequest{Query: "hinge", Limit: MaxPerPage, Offset: 40, Filter: []string{"is_active = 1"}},
+			limit:  MaxPerPage,
+			offset: 40,
+		},
+		{
+			name:   "page replaces limit and offset",
+			req:    dto.ProductSearchRequest{Query: "hinge", Page: 3, PerPage: 10, Limit: 50, Offset: 7, RawQuery: true},
+			want:   &meilisearch.SearchRequest{Query: "hinge", Page: 3, HitsPerPage: 10, Filter: []string{"is_active = 1"}},
+			limit:  10,
+			offset: 20,
+		},
+		{
+			name:   "per page capped at the maximum",
+			req:    dto.ProductSearchRequest{Query: "hinge", Page: 2, PerPage: 1000, RawQuery: true},
+			want:   &meilisearch.SearchRequest{Query: "hinge", Page: 2, HitsPerPage: MaxPerPage, Filter: []string{"is_active = 1"}},
+			limit:  MaxPerPage,
+			offset: MaxPerPage,
+		},
+		{
+			name: "highlight and crop",
+			req: dto.ProductSearchRequest{
+				Query: "hinge", RawQuery: true, IncludeInactive: true,
+				Highlight: []string{"name"}, Crop: []string{"description"}, CropLength: 12, CropMarker: "~",
+				HighlightPreTag: "<b>", HighlightPostTag: "</b>",
+			},
+			want: &meilisearch.SearchRequest{
+				Query: "hinge", Limit: DefaultPerPage,
+				AttributesToHighlight: []string{"name"}, AttributesToCrop: []string{"description"}, CropLength: 12, CropMarker: "~",
+				HighlightPreTag: "<b>", HighlightPostTag: "</b>", ShowMatchesPosition: true,
+			},
+			limit: DefaultPerPage,
+		},
+		{
+			name: "brand and category filters",
+			req: dto.ProductSearchRequest{
+				RawQuery: true, Category: "Hinges", CategoryPath: "Hardware > Hinges", Brand: "Hettich, Ebco ,",
+			},
+			want: &meilisearch.SearchRequest{Limit: DefaultPerPage, Filter: []string{
+				"is_active = 1",
+				`category_name = "Hinges"`,
+				`category.lvl1 = "Hardware > Hinges"`,
+				`brand IN ["Hettich", "Ebco"]`,
+			}},
+			limit: DefaultPerPage,
+		},
+		{
+			name:  "collapsed variants are distinct by group",
+			req:   dto.ProductSearchRequest{Query: "channel", RawQuery: true, IncludeInactive: true, CollapseVariants: true},
+			want:  &meilisearch.SearchRequest{Query: "channel", Limit: DefaultPerPage, Distinct: FieldVariantGroup},
+			limit: DefaultPerPage,
+		},
+		{
+			name:  "parsed measurements become filters",
+			req:   dto.ProductSearchRequest{Query: "ply 8x4", IncludeInactive: true},
+			want:  &meilisearch.SearchRequest{Query: "ply", Limit: DefaultPerPage, Filter: []string{`sheet_size = "8x4"`}},
+			limit: DefaultPerPage,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, searchRequest, _ := s.buildSearchRequest(tt.req)
+			if !reflect.DeepEqual(searchRequest, tt.want) {
+				t.Errorf("search request = %+v, want %+v", searchRequest, tt.want)
+			}
+			if req.Limit != tt.limit || req.Offset != tt.offset {
+				t.Errorf("limit, offset = %d, %d; want %d, %d", req.Limit, req.Offset, tt.limit, tt.offset)
+			}
+		})
+	}
+}
@@ -0,0 +1,225 @@
+package service
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"meilisearch/dto"
+)
+
+var (
+	// gluedMeasurementPattern splits tokens such as "18mm", "45kg" or `20"`
+	gluedMeasurementPattern = regexp.MustCompile(`^(\d+(?:\.\d+)?)([a-z"]+)$`)
+	// numberPattern matches a bare number token
+	numberPattern = regexp.MustCompile(`^\d+(?:\.\d+)?$`)
+	// sheetTokenPattern matches a sheet size token such as "8x4"
+	sheetTokenPattern = regexp.MustCompile(`^(\d)x(\d)$`)
+	// queryTokenSeparator splits queries into tokens
+	queryTokenSeparator = regexp.MustCompile(`[\s,]+`)
+)
+
+// crankTerms maps hinge overlay names to the crank they describe
+var crankTerms = map[string]int{
+	"full overlay": 0,
+	"straight arm": 0,
+	"half overlay": 8,
+	"full inset":   16,
+	"inset":        16,
+}
+
+// QueryParser turns measurements in search queries into attribute filters
+type QueryParser struct {
+	// tolerance is the relative difference allowed between a converted size
+	// and a nominal size, e.g. 16 in (406.4 mm) and a 400 mm channel
+	tolerance float64
+}
+
+// NewQueryParser creates a query parser with the given nominal size tolerance
+func NewQueryParser(tolerance float64) *QueryParser {
+	if tolerance < 0 {
+		tolerance = 0
+	}
+	return &QueryParser{tolerance: tolerance}
+}
+
+// ParseQuery parses a query using the default nominal size tolerance
+func ParseQuery(query string) *dto.ParsedQuery {
+	return NewQueryParser(DefaultUnitTolerance).Parse(query)
+}
+
+// Parse splits a search query into unit-aware attribute filters and the
+// remaining free text. "18 mm" and "18mm" are treated alike, sizes match in
+// both inches and millimetres, sheet sizes ("8x4") and hinge cranks
+// ("0 crank", "half overlay") become exact filters, and everything else stays in the text.
+func (p *QueryParser) Parse(query string) *dto.ParsedQuery {
+	parsed := &dto.ParsedQuery{
+		Measurements: []dto.QueryMeasurement{},
+		Applied:      true,
+	}
+
+	tokens := queryTokenSeparator.Split(strings.ToLower(strings.TrimSpace(query)), -1)
+	var text []string
+	for i := 0; i < len(tokens); i++ {
+		token := tokens[i]
+		if token == "" {
+			continue
+		}
+
+		// Sheet sizes: "8x4" or "8 x 4"
+		if match := sheetTokenPattern.FindStringSubmatch(token); match != nil {
+			parsed.Measurements = append(parsed.Measurements, sheetMeasurement(token, match[1], match[2]))
+			continue
+		}
+		if isSingleDigit(token) && i+2 < len(tokens) && tokens[i+1] == "x" && isSingleDigit(tokens[i+2]) {
+			raw := strings.Join(tokens[i:i+3], " ")
+			parsed.Measurements = append(parsed.Measurements, sheetMeasurement(raw, token, tokens[i+2]))
+			i += 2
+			continue
+		}
+
+		// Hinge overlay names: "full overlay", "inset"
+		if i+1 < len(tokens) {
+			raw := token + " " + tokens[i+1]
+			if crank, ok := crankTerms[raw]; ok {
+				parsed.Measurements = append(parsed.Measurements, crankMeasurement(raw, strconv.Itoa(crank)))
+				i++
+				continue
+			}
+		}
+		if crank, ok := crankTerms[token]; ok {
+			parsed.Measurements = append(parsed.Measurements, crankMeasurement(token, strconv.Itoa(crank)))
+			continue
+		}
+
+		// Glued number and unit: "18mm", "45kg"
+		if match := gluedMeasurementPattern.FindStringSubmatch(token); match != nil {
+			if m, ok := p.parseMeasurement(token, match[1], match[2]); ok {
+				parsed.Measurements = append(parsed.Measurements, m)
+				continue
+			}
+		}
+
+		// Number followed by a unit or "crank": "18 mm", "0 crank"
+		if numberPattern.MatchString(token) && i+1 < len(tokens) {
+			next := tokens[i+1]
+			raw := token + " " + next
+			if next == "crank" {
+				parsed.Measurements = append(parsed.Measurements, crankMeasurement(raw, token))
+				i++
+				continue
+			}
+			if m, ok := p.parseMeasurement(raw, token, next); ok {
+				parsed.Measurements = append(parsed.Measurements, m)
+				i++
+				continue
+			}
+		}
+
+		text = append(text, token)
+	}
+
+	parsed.Text = strings.Join(text, " ")
+	return parsed
+}
+
+// queryFilters returns the filter expressions of a parsed query
+func queryFilters(parsed *dto.ParsedQuery) []string {
+	var filters []string
+	for _, m := range parsed.Measurements {
+		filters = append(filters, m.Filter)
+	}
+	return filters
+}
+
+// parseMeasurement converts a number and unit token into a query measurement
+func (p *QueryParser) parseMeasurement(raw, number, unit string) (dto.QueryMeasurement, bool) {
+	value, err := strconv.ParseFloat(number, 64)
+	if err != nil {
+		return dto.QueryMeasurement{}, false
+	}
+	m, ok := newMeasurement(raw, value, unit)
+	if !ok {
+		return dto.QueryMeasurement{}, false
+	}
+
+	var filter string
+	switch m.kind {
+	case KindLength:
+		filter = p.lengthFilter(m)
+	case KindMass:
+		// A mass is a load rating for hardware and a pack weight for consumables
+		filter = "(" + FieldLoadKG + " = " + formatNumber(m.canonical) +
+			" OR " + FieldWeightG + " = " + formatNumber(roundCanonical(m.canonical*1000)) + ")"
+	case KindVolume:
+		filter = FieldVolumeML + " = " + formatNumber(m.canonical)
+	}
+
+	return dto.QueryMeasurement{
+		Raw:       raw,
+		Unit:      m.unit,
+		Value:     m.value,
+		Canonical: m.canonical,
+		Filter:    filter,
+	}, true
+}
+
+// lengthFilter matches a dimension that may be a thickness, height or length
+// depending on the product. Sizes given in the same unit as the product name
+// match exactly; sizes in the other unit match the nominal value within the
+// parser's tolerance, so "16 in" finds 400 mm channels and vice versa.
+func (p *QueryParser) lengthFilter(m measurement) string {
+	var clauses []string
+	if m.unit == "in" || m.unit == "ft" {
+		inches := roundCanonical(m.canonical / 25.4)
+		clauses = append(clauses,
+			FieldLengthIN+" = "+formatNumber(inches),
+			FieldHeightIN+" = "+formatNumber(inches),
+			p.rangeClause(FieldLengthMM, m.canonical),
+			p.rangeClause(FieldHeightMM, m.canonical),
+		)
+	} else {
+		inches := m.canonical / 25.4
+		clauses = append(clauses,
+			FieldThicknessMM+" = "+formatNumber(m.canonical),
+			FieldLengthMM+" = "+formatNumber(m.canonical),
+			FieldHeightMM+" = "+formatNumber(m.canonical),
+			p.rangeClause(FieldLengthIN, inches),
+			p.rangeClause(FieldHeightIN, inches),
+		)
+	}
+	return "(" + strings.Join(clauses, " OR ") + ")"
+}
+
+// rangeClause matches field within the parser's tolerance of value
+func (p *QueryParser) rangeClause(field string, value float64) string {
+	delta := value * p.tolerance
+	return field + " " + formatNumber(roundCanonical(value-delta)) + " TO " + formatNumber(roundCanonical(value+delta))
+}
+
+// sheetMeasurement builds a sheet size query measurement
+func sheetMeasurement(raw, a, b string) dto.QueryMeasurement {
+	size := normalizeSheetSize(a, b)
+	return dto.QueryMeasurement{
+		Raw:    raw,
+		Unit:   "sheet",
+		Filter: FieldSheetSize + " = " + quoteFilterValue(size),
+	}
+}
+
+// crankMeasurement builds a hinge crank query measurement
+func crankMeasurement(raw, number string) dto.QueryMeasurement {
+	value, _ := strconv.ParseFloat(number, 64)
+	return dto.QueryMeasurement{
+		Raw:       raw,
+		Unit:      "crank",
+		Value:     value,
+		Canonical: value,
+		Filter:    FieldCrank + " = " + formatNumber(value),
+	}
+}
+
+// isSingleDigit reports whether a token is a single digit
+func isSingleDigit(token string) bool {
+	return len(token) == 1 && token[0] >= '0' && token[0] <= '9'
+}
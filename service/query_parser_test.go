@@ -0,0 +1,192 @@
+package service
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseQuery(t *testing.T) {
+	tests := []struct {
+		name    string
+		query   string
+		text    string
+		units   []string
+		filters []string
+	}{
+		{
+			name:    "glued unit and sheet size",
+			query:   "18mm ply 8x4",
+			text:    "ply",
+			units:   []string{"mm", "sheet"},
+			filters: []string{"(thickness_mm = 18 OR length_mm = 18 OR height_mm = 18 OR length_in 0.69 TO 0.72 OR height_in 0.69 TO 0.72)", `sheet_size = "8x4"`},
+		},
+		{
+			name:    "spaced sheet size",
+			query:   "8 x 4 sheet",
+			text:    "sheet",
+			units:   []string{"sheet"},
+			filters: []string{`sheet_size = "8x4"`},
+		},
+		{
+			name:    "sheet size ordered largest first",
+			query:   "4x8",
+			units:   []string{"sheet"},
+			filters: []string{`sheet_size = "8x4"`},
+		},
+		{
+			name:    "mass matches load or pack weight",
+			query:   "channel 45kg",
+			text:    "channel",
+			units:   []string{"kg"},
+			filters: []string{"(load_kg = 45 OR weight_g = 45000)"},
+		},
+		{
+			name:    "volume in litres",
+			query:   "Fevicol 1 l",
+			text:    "fevicol",
+			units:   []string{"l"},
+			filters: []string{"volume_ml = 1000"},
+		},
+		{
+			name:    "numeric crank",
+			query:   "hinge 0 crank",
+			text:    "hinge",
+			units:   []string{"crank"},
+			filters: []string{"crank = 0"},
+		},
+		{
+			name:    "full overlay",
+			query:   "full overlay hinge",
+			text:    "hinge",
+			units:   []string{"crank"},
+			filters: []string{"crank = 0"},
+		},
+		{
+			name:    "straight arm",
+			query:   "straight arm hinge",
+			text:    "hinge",
+			units:   []string{"crank"},
+			filters: []string{"crank = 0"},
+		},
+		{
+			name:    "half overlay",
+			query:   "Half Overlay hinge",
+			text:    "hinge",
+			units:   []string{"crank"},
+			filters: []string{"crank = 8"},
+		},
+		{
+			name:    "full inset",
+			query:   "hinge full inset",
+			text:    "hinge",
+			units:   []string{"crank"},
+			filters: []string{"crank = 16"},
+		},
+		{
+			name:    "inset",
+			query:   "inset hinge",
+			text:    "hinge",
+			units:   []string{"crank"},
+			filters: []string{"crank = 16"},
+		},
+		{
+			name:  "bare number stays in the text",
+			query: "hinge 18",
+			text:  "hinge 18",
+		},
+		{
+			name:  "unknown unit stays in the text",
+			query: "18 pcs",
+			text:  "18 pcs",
+		},
+		{
+			name:  "commas and extra spaces",
+			query: "  tandem,  box ",
+			text:  "tandem box",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parsed := ParseQuery(tt.query)
+			if parsed.Text != tt.text {
+				t.Errorf("text = %q, want %q", parsed.Text, tt.text)
+			}
+			var units []string
+			for _, m := range parsed.Measurements {
+				units = append(units, m.Unit)
+			}
+			if !reflect.DeepEqual(units, tt.units) {
+				t.Errorf("units = %q, want %q", units, tt.units)
+			}
+			if filters := queryFilters(parsed); !reflect.DeepEqual(filters, tt.filters) {
+				t.Errorf("filters = %q, want %q", filters, tt.filters)
+			}
+			if !parsed.Applied {
+				t.Error("applied = false, want true")
+			}
+		})
+	}
+}
+
+func TestQueryParserTolerance(t *testing.T) {
+	tests := []struct {
+		name      string
+		tolerance float64
+		query     string
+		canonical float64
+		filter    string
+	}{
+		{
+			name:      "inches match nominal millimetres within the default tolerance",
+			tolerance: DefaultUnitTolerance,
+			query:     "16 in",
+			canonical: 406.4,
+			filter:    "(length_in = 16 OR height_in = 16 OR length_mm 398.27 TO 414.53 OR height_mm 398.27 TO 414.53)",
+		},
+		{
+			name:      "millimetres match nominal inches within the default tolerance",
+			tolerance: DefaultUnitTolerance,
+			query:     "400mm",
+			canonical: 400,
+			filter:    "(thickness_mm = 400 OR length_mm = 400 OR height_mm = 400 OR length_in 15.43 TO 16.06 OR height_in 15.43 TO 16.06)",
+		},
+		{
+			name:      "wider tolerance",
+			tolerance: 0.05,
+			query:     "16 inch",
+			canonical: 406.4,
+			filter:    "(length_in = 16 OR height_in = 16 OR length_mm 386.08 TO 426.72 OR height_mm 386.08 TO 426.72)",
+		},
+		{
+			name:      "feet convert to inches",
+			tolerance: 0,
+			query:     "2 ft",
+			canonical: 609.6,
+			filter:    "(length_in = 24 OR height_in = 24 OR length_mm 609.6 TO 609.6 OR height_mm 609.6 TO 609.6)",
+		},
+		{
+			name:      "negative tolerance is treated as exact",
+			tolerance: -1,
+			query:     "16 in",
+			canonical: 406.4,
+			filter:    "(length_in = 16 OR height_in = 16 OR length_mm 406.4 TO 406.4 OR height_mm 406.4 TO 406.4)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parsed := NewQueryParser(tt.tolerance).Parse(tt.query)
+			if len(parsed.Measurements) != 1 {
+				t.Fatalf("got %d measurements, want 1", len(parsed.Measurements))
+			}
+			m := parsed.Measurements[0]
+			if m.Canonical != tt.canonical {
+				t.Errorf("canonical = %v, want %v", m.Canonical, tt.canonical)
+			}
+			if m.Filter != tt.filter {
+				t.Errorf("filter = %q, want %q", m.Filter, tt.filter)
+			}
+		})
+	}
+}
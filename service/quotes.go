@@ -0,0 +1,470 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"meilisearch/dto"
+
+	"github.com/meilisearch/meilisearch-go"
+)
+
+// DefaultQuotesDir is the directory quotes are saved to, one JSON file each
+const DefaultQuotesDir = "data/quotes"
+
+// QuoteCurrency is the currency of catalog prices
+const QuoteCurrency = "INR"
+
+// MaxQuoteItems caps the number of lines in one quote
+const MaxQuoteItems = 200
+
+// Price states of a quote line
+const (
+	PriceStatusPriced  = "priced"
+	PriceStatusMissing = "missing_price"
+	PriceStatusInvalid = "invalid_price"
+)
+
+// Quote errors
+var (
+	ErrQuoteNotFound = errors.New("quote not found")
+	ErrInvalidQuote  = errors.New("invalid quote")
+)
+
+var (
+	// quoteIDPattern matches generated quote IDs such as "Q-20250612-1a2b3c4d"
+	quoteIDPattern = regexp.MustCompile(`^Q-\d{8}-[0-9a-f]{8}$`)
+	// priceNoisePattern matches currency symbols, separators and spaces in prices
+	priceNoisePattern = regexp.MustCompile(`[₹,\s]|(?i)rs\.?|inr`)
+)
+
+// QuoteService builds priced quotes from catalog products and saves them
+// as JSON files
+type QuoteService struct {
+	mu    sync.Mutex
+	dir   string
+	index meilisearch.IndexManager
+}
+
+// NewQuoteService creates a quote service saving quotes to dir
+func NewQuoteService(client meilisearch.ServiceManager, dir string) *QuoteService {
+	return &QuoteService{
+		dir:   dir,
+		index: client.Index(ProductIndexUID),
+	}
+}
+
+// Create prices the requested items and saves the quote. Prices are copied
+// into the quote, so later catalog changes do not alter it. Lines whose
+// product has no price are kept with empty amounts and excluded from the totals.
+func (s *QuoteService) Create(ctx context.Context, req dto.QuoteCreateRequest) (*dto.Quote, error) {
+	if len(req.Items) == 0 {
+		return nil, fmt.Errorf("%w: at least one item is required", ErrInvalidQuote)
+	}
+	if len(req.Items) > MaxQuoteItems {
+		return nil, fmt.Errorf("%w: at most %d items are allowed", ErrInvalidQuote, MaxQuoteItems)
+	}
+	for i, item := range req.Items {
+		if item.ProductID <= 0 && strings.TrimSpace(item.SKU) == "" {
+			return nil, fmt.Errorf("%w: item %d needs a product_id or sku", ErrInvalidQuote, i+1)
+		}
+		if item.Quantity <= 0 {
+			return nil, fmt.Errorf("%w: item %d needs a positive quantity", ErrInvalidQuote, i+1)
+		}
+	}
+
+	byID, bySKU, err := s.lookupProducts(ctx, req.Items)
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := newQuoteID(time.Now())
+	if err != nil {
+		return nil, err
+	}
+	quote := &dto.Quote{
+		ID:        id,
+		Customer:  req.Customer,
+		Notes:     req.Notes,
+		Currency:  QuoteCurrency,
+		CreatedAt: time.Now().UTC(),
+		Lines:     make([]dto.QuoteLine, 0, len(req.Items)),
+	}
+	for i, item := range req.Items {
+		product, err := quoteProduct(i+1, item, byID, bySKU)
+		if err != nil {
+			return nil, err
+		}
+		quote.Lines = append(quote.Lines, priceLine(i+1, product, item.Quantity))
+	}
+	quote.Totals = quoteTotals(quote.Lines)
+
+	if err := s.save(quote); err != nil {
+		return nil, err
+	}
+	return quote, nil
+}
+
+// Get loads a saved quote
+func (s *QuoteService) Get(id string) (*dto.Quote, error) {
+	if !quoteIDPattern.MatchString(id) {
+		return nil, ErrQuoteNotFound
+	}
+
+	data, err := os.ReadFile(s.quotePath(id))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, ErrQuoteNotFound
+		}
+		return nil, err
+	}
+
+	var quote dto.Quote
+	if err := json.Unmarshal(data, &quote); err != nil {
+		return nil, fmt.Errorf("failed to parse quote %s: %w", id, err)
+	}
+	return &quote, nil
+}
+
+// List returns summaries of all saved quotes, newest first
+func (s *QuoteService) List() ([]dto.QuoteSummary, error) {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return []dto.QuoteSummary{}, nil
+		}
+		return nil, err
+	}
+
+	summaries := []dto.QuoteSummary{}
+	for _, entry := range entries {
+		id := strings.TrimSuffix(entry.Name(), ".json")
+		if entry.IsDir() || !quoteIDPattern.MatchString(id) {
+			continue
+		}
+		quote, err := s.Get(id)
+		if err != nil {
+			return nil, err
+		}
+		summaries = append(summaries, dto.QuoteSummary{
+			ID:        quote.ID,
+			Customer:  quote.Customer,
+			CreatedAt: quote.CreatedAt,
+			Lines:     len(quote.Lines),
+			Total:     quote.Totals.Total,
+			Complete:  quote.Totals.Complete,
+		})
+	}
+	sort.Slice(summaries, func(i, j int) bool {
+		return summaries[i].CreatedAt.After(summaries[j].CreatedAt)
+	})
+	return summaries, nil
+}
+
+// Delete removes a saved quote
+func (s *QuoteService) Delete(id string) error {
+	if !quoteIDPattern.MatchString(id) {
+		return ErrQuoteNotFound
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := os.Remove(s.quotePath(id)); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return ErrQuoteNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+// WriteQuoteCSV writes a quote as CSV: one row per line followed by the
+// totals. Missing amounts are left empty, and text taken from the catalog is
+// escaped so spreadsheets do not run it as a formula.
+func WriteQuoteCSV(w io.Writer, quote *dto.Quote) error {
+	writer := csv.NewWriter(w)
+	rows := [][]string{{
+		"line", "product_id", "sku", "name", "category", "quantity", "unit_type",
+		"unit_price", "discount_percent", "gross", "discount_amount", "total", "price_status",
+	}}
+	for _, line := range quote.Lines {
+		unitType := ""
+		if line.UnitType != nil {
+			unitType = *line.UnitType
+		}
+		rows = append(rows, []string{
+			strconv.Itoa(line.Line),
+			strconv.Itoa(line.ProductID),
+			csvText(line.SKU),
+			csvText(line.Name),
+			csvText(line.CategoryName),
+			strconv.Itoa(line.Quantity),
+			csvText(unitType),
+			formatAmount(line.UnitPrice),
+			formatAmount(line.DiscountPercent),
+			formatAmount(line.Gross),
+			formatAmount(line.DiscountAmount),
+			formatAmount(line.Total),
+			line.PriceStatus,
+		})
+	}
+
+	totals := quote.Totals
+	rows = append(rows,
+		[]string{},
+		[]string{"gross", "", "", "", "", "", "", "", "", formatMoney(totals.Gross)},
+		[]string{"discount", "", "", "", "", "", "", "", "", "", formatMoney(totals.Discount)},
+		[]string{"total", "", "", "", "", "", "", "", "", "", "", formatMoney(totals.Total)},
+		[]string{"missing_price_lines", strconv.Itoa(totals.MissingPriceLines)},
+	)
+
+	if err := writer.WriteAll(rows); err != nil {
+		return err
+	}
+	return writer.Error()
+}
+
+// csvText prefixes text starting with a formula character with a quote, so
+// spreadsheets show a name such as "=HYPERLINK(...)" instead of evaluating it
+func csvText(value string) string {
+	if value != "" && strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
+
+// lookupProducts fetches the products referenced by quote items in one request
+func (s *QuoteService) lookupProducts(ctx context.Context, items []dto.QuoteItemRequest) (map[int]dto.Product, map[string]dto.Product, error) {
+	var ids, skus []string
+	for _, item := range items {
+		if item.ProductID > 0 {
+			ids = append(ids, strconv.Itoa(item.ProductID))
+		} else {
+			skus = append(skus, quoteFilterValue(strings.TrimSpace(item.SKU)))
+		}
+	}
+
+	var clauses []string
+	if len(ids) > 0 {
+		clauses = append(clauses, "id IN ["+strings.Join(ids, ", ")+"]")
+	}
+	if len(skus) > 0 {
+		clauses = append(clauses, "sku IN ["+strings.Join(skus, ", ")+"]")
+	}
+
+	products, err := fetchProducts(ctx, s.index, strings.Join(clauses, " OR "), len(items))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	byID := make(map[int]dto.Product, len(products))
+	bySKU := make(map[string]dto.Product, len(products))
+	for _, product := range products {
+		byID[product.ID] = product
+		bySKU[product.SKU] = product
+	}
+	return byID, bySKU, nil
+}
+
+// quoteProduct returns the product a quote item refers to. Missing and
+// inactive products cannot be quoted.
+func quoteProduct(number int, item dto.QuoteItemRequest, byID map[int]dto.Product, bySKU map[string]dto.Product) (dto.Product, error) {
+	product, ok := byID[item.ProductID]
+	reference := strconv.Itoa(item.ProductID)
+	if item.ProductID <= 0 {
+		reference = strings.TrimSpace(item.SKU)
+		product, ok = bySKU[reference]
+	}
+	if !ok {
+		return dto.Product{}, fmt.Errorf("%w: item %d: product %s not found", ErrInvalidQuote, number, reference)
+	}
+	if product.IsActive != 1 {
+		return dto.Product{}, fmt.Errorf("%w: item %d: product %s is not available", ErrInvalidQuote, number, reference)
+	}
+	return product, nil
+}
+
+// priceLine prices one quote line. The unit price is selling_price, or
+// per_unit_selling_price times unit_value when only the per-unit price is
+// known; discount is a percentage taken off the gross amount.
+func priceLine(number int, product dto.Product, quantity int) dto.QuoteLine {
+	line := dto.QuoteLine{
+		Line:         number,
+		ProductID:    product.ID,
+		SKU:          product.SKU,
+		Name:         product.Name,
+		CategoryName: product.CategoryName,
+		Quantity:     quantity,
+		UnitType:     product.UnitType,
+	}
+
+	unitPrice, source, status := unitPrice(product)
+	line.PriceStatus = status
+	if status != PriceStatusPriced {
+		if status == PriceStatusInvalid {
+			line.Warnings = append(line.Warnings, "price in the catalog could not be read")
+		}
+		return line
+	}
+	line.PriceSource = source
+
+	discount := 0.0
+	if value, present, ok := parseAmount(product.Discount); present {
+		if ok && value >= 0 && value <= 100 {
+			discount = value
+			line.DiscountPercent = &discount
+		} else {
+			line.Warnings = append(line.Warnings, "discount ignored: not a percentage between 0 and 100")
+		}
+	}
+
+	gross := roundMoney(unitPrice * float64(quantity))
+	discountAmount := roundMoney(gross * discount / 100)
+	total := roundMoney(gross - discountAmount)
+	line.UnitPrice = &unitPrice
+	line.Gross = &gross
+	line.DiscountAmount = &discountAmount
+	line.Total = &total
+	return line
+}
+
+// unitPrice returns the unit price of a product, the field it came from and
+// the resulting price status
+func unitPrice(product dto.Product) (float64, string, string) {
+	price, present, ok := parseAmount(product.SellingPrice)
+	if present {
+		if !ok || price < 0 {
+			return 0, "", PriceStatusInvalid
+		}
+		return roundMoney(price), "selling_price", PriceStatusPriced
+	}
+
+	perUnit, present, ok := parseAmount(product.PerUnitSellingPrice)
+	if !present {
+		return 0, "", PriceStatusMissing
+	}
+	if !ok || perUnit < 0 {
+		return 0, "", PriceStatusInvalid
+	}
+	if units, present, ok := parseAmount(product.UnitValue); present && ok && units > 0 {
+		return roundMoney(perUnit * units), "per_unit_selling_price x unit_value", PriceStatusPriced
+	}
+	return roundMoney(perUnit), "per_unit_selling_price", PriceStatusPriced
+}
+
+// quoteTotals sums the priced lines of a quote
+func quoteTotals(lines []dto.QuoteLine) dto.QuoteTotals {
+	var totals dto.QuoteTotals
+	for _, line := range lines {
+		if line.Total == nil {
+			totals.MissingPriceLines++
+			continue
+		}
+		totals.PricedLines++
+		totals.Gross += *line.Gross
+		totals.Discount += *line.DiscountAmount
+		totals.Total += *line.Total
+	}
+	totals.Gross = roundMoney(totals.Gross)
+	totals.Discount = roundMoney(totals.Discount)
+	totals.Total = roundMoney(totals.Total)
+	totals.Complete = totals.MissingPriceLines == 0
+	return totals
+}
+
+// parseAmount parses a catalog amount such as "1,250.00" or "₹ 499". present
+// is false for missing values and ok is false for unreadable ones.
+func parseAmount(value *string) (amount float64, present bool, ok bool) {
+	if value == nil {
+		return 0, false, false
+	}
+	raw := strings.TrimSpace(*value)
+	if raw == "" || strings.EqualFold(raw, "NULL") {
+		return 0, false, false
+	}
+
+	raw = strings.TrimSuffix(priceNoisePattern.ReplaceAllString(raw, ""), "%")
+	amount, err := strconv.ParseFloat(raw, 64)
+	if err != nil || math.IsNaN(amount) || math.IsInf(amount, 0) {
+		return 0, true, false
+	}
+	return amount, true, true
+}
+
+// roundMoney rounds an amount to paise
+func roundMoney(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
+
+// formatMoney formats an amount with two decimals
+func formatMoney(amount float64) string {
+	return strconv.FormatFloat(amount, 'f', 2, 64)
+}
+
+// formatAmount formats an optional amount, leaving missing ones empty
+func formatAmount(amount *float64) string {
+	if amount == nil {
+		return ""
+	}
+	return formatMoney(*amount)
+}
+
+// newQuoteID generates a quote ID from the creation date and random bytes
+func newQuoteID(now time.Time) (string, error) {
+	suffix := make([]byte, 4)
+	if _, err := rand.Read(suffix); err != nil {
+		return "", err
+	}
+	return "Q-" + now.UTC().Format("20060102") + "-" + hex.EncodeToString(suffix), nil
+}
+
+// quotePath returns the file a quote is saved to
+func (s *QuoteService) quotePath(id string) string {
+	return filepath.Join(s.dir, id+".json")
+}
+
+// save writes a quote to disk, replacing the file atomically
+func (s *QuoteService) save(quote *dto.Quote) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := os.MkdirAll(s.dir, 0o755); err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(quote, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(s.dir, ".quote-*.json")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(append(data, '\n')); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), s.quotePath(quote.ID))
+}
@@ -0,0 +1,259 @@
+package service
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"reflect"
+	"testing"
+
+	"meilisearch/dto"
+)
+
+func TestParseAmount(t *testing.T) {
+	tests := []struct {
+		value   *string
+		amount  float64
+		present bool
+		ok      bool
+	}{
+		{nil, 0, false, false},
+		{ptr(""), 0, false, false},
+		{ptr(" NULL "), 0, false, false},
+		{ptr("499"), 499, true, true},
+		{ptr("1,250.00"), 1250, true, true},
+		{ptr("₹ 499"), 499, true, true},
+		{ptr("Rs. 120"), 120, true, true},
+		{ptr("INR 75.5"), 75.5, true, true},
+		{ptr("12.5%"), 12.5, true, true},
+		{ptr("on request"), 0, true, false},
+		{ptr("NaN"), 0, true, false},
+		{ptr("Inf"), 0, true, false},
+	}
+
+	for _, tt := range tests {
+		name := "nil"
+		if tt.value != nil {
+			name = *tt.value
+		}
+		t.Run(name, func(t *testing.T) {
+			amount, present, ok := parseAmount(tt.value)
+			if amount != tt.amount || present != tt.present || ok != tt.ok {
+				t.Errorf("parseAmount(%q) = %v, %v, %v; want %v, %v, %v",
+					name, amount, present, ok, tt.amount, tt.present, tt.ok)
+			}
+		})
+	}
+}
+
+func TestPriceLine(t *testing.T) {
+	tests := []struct {
+		name      string
+		product   dto.Product
+		status    string
+		source    string
+		unitPrice *float64
+		discount  *float64
+		gross     *float64
+		total     *float64
+		warnings  []string
+	}{
+		{
+			name:      "selling price with discount",
+			product:   dto.Product{SellingPrice: ptr("100"), Discount: ptr("10")},
+			status:    PriceStatusPriced,
+			source:    "selling_price",
+			unitPrice: ptr(100.0),
+			discount:  ptr(10.0),
+			gross:     ptr(300.0),
+			total:     ptr(270.0),
+		},
+		{
+			name:      "per unit price times unit value",
+			product:   dto.Product{PerUnitSellingPrice: ptr("2.5"), UnitValue: ptr("4")},
+			status:    PriceStatusPriced,
+			source:    "per_unit_selling_price x unit_value",
+			unitPrice: ptr(10.0),
+			gross:     ptr(30.0),
+			total:     ptr(30.0),
+		},
+		{
+			name:      "per unit price without unit value",
+			product:   dto.Product{PerUnitSellingPrice: ptr("2.5")},
+			status:    PriceStatusPriced,
+			source:    "per_unit_selling_price",
+			unitPrice: ptr(2.5),
+			gross:     ptr(7.5),
+			total:     ptr(7.5),
+		},
+		{
+			name:      "amounts rounded to paise",
+			product:   dto.Product{SellingPrice: ptr("33.333"), Discount: ptr("5")},
+			status:    PriceStatusPriced,
+			source:    "selling_price",
+			unitPrice: ptr(33.33),
+			discount:  ptr(5.0),
+			gross:     ptr(99.99),
+			total:     ptr(94.99),
+		},
+		{
+			name:      "discount out of range",
+			product:   dto.Product{SellingPrice: ptr("100"), Discount: ptr("150")},
+			status:    PriceStatusPriced,
+			source:    "selling_price",
+			unitPrice: ptr(100.0),
+			gross:     ptr(300.0),
+			total:     ptr(300.0),
+			warnings:  []string{"discount ignored: not a percentage between 0 and 100"},
+		},
+		{
+			name:    "missing price",
+			product: dto.Product{SellingPrice: ptr("NULL")},
+			status:  PriceStatusMissing,
+		},
+		{
+			name:     "unreadable price",
+			product:  dto.Product{SellingPrice: ptr("on request")},
+			status:   PriceStatusInvalid,
+			warnings: []string{"price in the catalog could not be read"},
+		},
+		{
+			name:     "negative price",
+			product:  dto.Product{SellingPrice: ptr("-5")},
+			status:   PriceStatusInvalid,
+			warnings: []string{"price in the catalog could not be read"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			line := priceLine(1, tt.product, 3)
+			if line.PriceStatus != tt.status || line.PriceSource != tt.source {
+				t.Errorf("status, source = %q, %q; want %q, %q", line.PriceStatus, line.PriceSource, tt.status, tt.source)
+			}
+			checkAmount(t, "unit price", line.UnitPrice, tt.unitPrice)
+			checkAmount(t, "discount", line.DiscountPercent, tt.discount)
+			checkAmount(t, "gross", line.Gross, tt.gross)
+			checkAmount(t, "total", line.Total, tt.total)
+			if !reflect.DeepEqual(line.Warnings, tt.warnings) {
+				t.Errorf("warnings = %q, want %q", line.Warnings, tt.warnings)
+			}
+		})
+	}
+}
+
+func TestQuoteProduct(t *testing.T) {
+	active := dto.Product{ID: 1, SKU: "HNG-01", IsActive: 1}
+	inactive := dto.Product{ID: 2, SKU: "HNG-02", IsActive: 0}
+	byID := map[int]dto.Product{1: active, 2: inactive}
+	bySKU := map[string]dto.Product{"HNG-01": active, "HNG-02": inactive}
+
+	tests := []struct {
+		name string
+		item dto.QuoteItemRequest
+		want int
+		err  string
+	}{
+		{name: "active by id", item: dto.QuoteItemRequest{ProductID: 1}, want: 1},
+		{name: "active by sku", item: dto.QuoteItemRequest{SKU: " HNG-01 "}, want: 1},
+		{name: "missing id", item: dto.QuoteItemRequest{ProductID: 9}, err: "invalid quote: item 1: product 9 not found"},
+		{name: "missing sku", item: dto.QuoteItemRequest{SKU: "HNG-09"}, err: "invalid quote: item 1: product HNG-09 not found"},
+		{name: "inactive id", item: dto.QuoteItemRequest{ProductID: 2}, err: "invalid quote: item 1: product 2 is not available"},
+		{name: "inactive sku", item: dto.QuoteItemRequest{SKU: "HNG-02"}, err: "invalid quote: item 1: product HNG-02 is not available"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			product, err := quoteProduct(1, tt.item, byID, bySKU)
+			if tt.err != "" {
+				if err == nil || err.Error() != tt.err || !errors.Is(err, ErrInvalidQuote) {
+					t.Errorf("error = %v, want %q", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if product.ID != tt.want {
+				t.Errorf("product = %d, want %d", product.ID, tt.want)
+			}
+		})
+	}
+}
+
+func TestQuoteTotals(t *testing.T) {
+	lines := []dto.QuoteLine{
+		priceLine(1, dto.Product{SellingPrice: ptr("100"), Discount: ptr("10")}, 3),
+		priceLine(2, dto.Product{SellingPrice: ptr("0.1")}, 1),
+		priceLine(3, dto.Product{}, 5),
+	}
+	want := dto.QuoteTotals{Gross: 300.1, Discount: 30, Total: 270.1, PricedLines: 2, MissingPriceLines: 1}
+	if got := quoteTotals(lines); got != want {
+		t.Errorf("quoteTotals() = %+v, want %+v", got, want)
+	}
+}
+
+func TestCSVText(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{"", ""},
+		{"Hettich soft close hinge", "Hettich soft close hinge"},
+		{"=HYPERLINK(\"http://x\")", "'=HYPERLINK(\"http://x\")"},
+		{"+91 hinge", "'+91 hinge"},
+		{"-2+3", "'-2+3"},
+		{"@SUM(A1)", "'@SUM(A1)"},
+		{"\t=1", "'\t=1"},
+		{"a=b", "a=b"},
+	}
+
+	for _, tt := range tests {
+		if got := csvText(tt.value); got != tt.want {
+			t.Errorf("csvText(%q) = %q, want %q", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestWriteQuoteCSV(t *testing.T) {
+	quote := &dto.Quote{Lines: []dto.QuoteLine{
+		priceLine(1, dto.Product{ID: 7, SKU: "-SKU1", Name: "=cmd()", CategoryName: "@Hinges", SellingPrice: ptr("10")}, 2),
+	}}
+	quote.Totals = quoteTotals(quote.Lines)
+
+	var buf bytes.Buffer
+	if err := WriteQuoteCSV(&buf, quote); err != nil {
+		t.Fatalf("WriteQuoteCSV() error = %v", err)
+	}
+	reader := csv.NewReader(&buf)
+	reader.FieldsPerRecord = -1
+	rows, err := reader.ReadAll()
+	if err != nil {
+		t.Fatalf("reading CSV: %v", err)
+	}
+
+	want := []string{"1", "7", "'-SKU1", "'=cmd()", "'@Hinges", "2", "", "10.00", "", "20.00", "0.00", "20.00", PriceStatusPriced}
+	if !reflect.DeepEqual(rows[1], want) {
+		t.Errorf("line row = %q, want %q", rows[1], want)
+	}
+	if last := rows[len(rows)-1]; !reflect.DeepEqual(last, []string{"missing_price_lines", "0"}) {
+		t.Errorf("last row = %q", last)
+	}
+}
+
+// checkAmount compares an optional amount
+func checkAmount(t *testing.T, name string, got, want *float64) {
+	t.Helper()
+	switch {
+	case got == nil && want == nil:
+	case got == nil || want == nil:
+		t.Errorf("%s = %v, want %v", name, got, want)
+	case *got != *want:
+		t.Errorf("%s = %v, want %v", name, *got, *want)
+	}
+}
+
+// ptr returns a pointer to a value
+func ptr[T any](value T) *T {
+	return &value
+}
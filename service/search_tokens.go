@@ -0,0 +1,196 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"meilisearch/dto"
+
+	"github.com/meilisearch/meilisearch-go"
+)
+
+// Defaults for search token lifetimes
+const (
+	DefaultSearchTokenTTL    = time.Hour
+	DefaultSearchTokenMaxTTL = 24 * time.Hour
+)
+
+// searchAction is the Meilisearch key action tenant tokens rely on
+const searchAction = "search"
+
+var (
+	// ErrSearchTokensDisabled is returned when no search API key is configured
+	ErrSearchTokensDisabled = errors.New("search tokens are not configured")
+	// ErrInvalidSearchToken is returned for a token request with an invalid
+	// lifetime
+	ErrInvalidSearchToken = errors.New("invalid search token request")
+	// ErrRestrictionNotAllowed is returned when a caller limited to some
+	// categories asks for others
+	ErrRestrictionNotAllowed = errors.New("category not allowed for this caller")
+)
+
+// SearchTokenService mints Meilisearch tenant tokens that let browsers search
+// the product index directly. Every token only sees active products, and can
+// be narrowed further to some categories or brands.
+type SearchTokenService struct {
+	client meilisearch.ServiceManager
+	keyUID string
+	host   string
+	ttl    time.Duration
+	maxTTL time.Duration
+
+	mu  sync.Mutex
+	key string
+}
+
+// SearchTokenOption configures a SearchTokenService
+type SearchTokenOption func(*SearchTokenService)
+
+// WithSearchKey sets the search API key tokens are signed with. Without it
+// the key is fetched from Meilisearch by UID on first use, which needs the
+// master key.
+func WithSearchKey(key string) SearchTokenOption {
+	return func(s *SearchTokenService) {
+		s.key = key
+	}
+}
+
+// WithSearchTokenTTL sets the lifetime of tokens whose request does not ask
+// for one
+func WithSearchTokenTTL(ttl time.Duration) SearchTokenOption {
+	return func(s *SearchTokenService) {
+		s.ttl = ttl
+	}
+}
+
+// WithSearchTokenMaxTTL sets the longest lifetime a request may ask for
+func WithSearchTokenMaxTTL(ttl time.Duration) SearchTokenOption {
+	return func(s *SearchTokenService) {
+		s.maxTTL = ttl
+	}
+}
+
+// WithSearchHost sets the Meilisearch address returned to browsers, when it
+// differs from the one the server uses
+func WithSearchHost(host string) SearchTokenOption {
+	return func(s *SearchTokenService) {
+		s.host = host
+	}
+}
+
+// NewSearchTokenService creates a service minting tokens for the search API
+// key with the given UID. An empty UID disables token minting.
+func NewSearchTokenService(client meilisearch.ServiceManager, keyUID string, opts ...SearchTokenOption) *SearchTokenService {
+	s := &SearchTokenService{
+		client: client,
+		keyUID: keyUID,
+		ttl:    DefaultSearchTokenTTL,
+		maxTTL: DefaultSearchTokenMaxTTL,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// Create mints a tenant token scoped to the product index. allowed lists the
+// categories the caller is limited to; when set, the requested categories
+// must be among them and default to all of them.
+func (s *SearchTokenService) Create(ctx context.Context, req dto.SearchTokenRequest, allowed []string) (*dto.SearchTokenResponse, error) {
+	if s.keyUID == "" {
+		return nil, ErrSearchTokensDisabled
+	}
+
+	ttl := s.ttl
+	if req.ExpiresIn != 0 {
+		ttl = time.Duration(req.ExpiresIn) * time.Second
+	}
+	if ttl <= 0 || ttl > s.maxTTL {
+		return nil, fmt.Errorf("%w: expires_in must be between 1 and %d seconds",
+			ErrInvalidSearchToken, int(s.maxTTL.Seconds()))
+	}
+
+	categories := req.Categories
+	if len(allowed) > 0 {
+		for _, category := range categories {
+			if !slices.Contains(allowed, category) {
+				return nil, fmt.Errorf("%w: %s", ErrRestrictionNotAllowed, category)
+			}
+		}
+		if len(categories) == 0 {
+			categories = allowed
+		}
+	}
+
+	key, err := s.searchKey(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := strings.Join(tokenFilter(categories, req.Brands), " AND ")
+	expiresAt := time.Now().Add(ttl).UTC().Truncate(time.Second)
+	rules := map[string]interface{}{
+		ProductIndexUID: map[string]interface{}{"filter": filter},
+	}
+	token, err := s.client.GenerateTenantToken(s.keyUID, rules, &meilisearch.TenantTokenOptions{
+		APIKey:    key,
+		ExpiresAt: expiresAt,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign search token: %w", err)
+	}
+
+	return &dto.SearchTokenResponse{
+		Token:     token,
+		Host:      s.host,
+		IndexUID:  ProductIndexUID,
+		Filter:    filter,
+		ExpiresAt: expiresAt,
+	}, nil
+}
+
+// searchKey returns the search API key tokens are signed with, fetching it
+// from Meilisearch the first time when it was not configured
+func (s *SearchTokenService) searchKey(ctx context.Context) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.key != "" {
+		return s.key, nil
+	}
+	key, err := s.client.GetKeyWithContext(ctx, s.keyUID)
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch search key %s: %w", s.keyUID, err)
+	}
+	if !slices.Contains(key.Actions, searchAction) && !slices.Contains(key.Actions, "*") {
+		return "", fmt.Errorf("key %s cannot be used for search tokens: it lacks the %q action", s.keyUID, searchAction)
+	}
+	s.key = key.Key
+	return s.key, nil
+}
+
+// tokenFilter builds the filter embedded in a tenant token
+func tokenFilter(categories, brands []string) []string {
+	filter := []string{"is_active = 1"}
+	if len(categories) > 0 {
+		filter = append(filter, "category_name IN ["+quoteFilterValues(categories)+"]")
+	}
+	if len(brands) > 0 {
+		filter = append(filter, FieldBrand+" IN ["+quoteFilterValues(brands)+"]")
+	}
+	return filter
+}
+
+// quoteFilterValues quotes strings for a Meilisearch IN [...] list
+func quoteFilterValues(values []string) string {
+	quoted := make([]string, len(values))
+	for i, value := range values {
+		quoted[i] = quoteFilterValue(value)
+	}
+	return strings.Join(quoted, ", ")
+}
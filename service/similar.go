@@ -0,0 +1,268 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"math"
+	"sort"
+	"strings"
+
+	"meilisearch/dto"
+
+	"github.com/meilisearch/meilisearch-go"
+)
+
+// Brand scopes for similar-product recommendations
+const (
+	BrandScopeAny   = "any"
+	BrandScopeSame  = "same"
+	BrandScopeOther = "other"
+)
+
+// Limits applied to similar-product recommendations
+const (
+	DefaultSimilarLimit = 10
+	MaxSimilarLimit     = 50
+	// similarCandidates is the number of text matches re-ranked by attributes
+	similarCandidates = 100
+)
+
+// Weights of the text and attribute scores in a similarity score
+const (
+	similarTextWeight      = 0.4
+	similarAttributeWeight = 0.6
+	// similarTolerance is the relative difference reported as a similar size
+	similarTolerance = 0.1
+)
+
+var (
+	// ErrInvalidBrandScope is returned for a brand scope other than any, same or other
+	ErrInvalidBrandScope = errors.New("brand scope must be any, same or other")
+	// ErrNoBrand is returned when a brand scope is requested for a product
+	// without a recognized brand
+	ErrNoBrand = errors.New("product has no recognized brand")
+)
+
+// similarAttribute describes how one extracted attribute is compared
+type similarAttribute struct {
+	field string
+	label string
+	unit  string
+}
+
+// similarAttributes are the attributes that make two products interchangeable
+var similarAttributes = []similarAttribute{
+	{FieldThicknessMM, "thickness", "mm"},
+	{FieldLengthMM, "length", "mm"},
+	{FieldHeightMM, "height", "mm"},
+	{FieldLoadKG, "load", "kg"},
+	{FieldWeightG, "pack size", "g"},
+	{FieldVolumeML, "pack size", "ml"},
+	{FieldSheetSize, "sheet size", ""},
+	{FieldCrank, "crank", ""},
+	{FieldCloseType, "close type", ""},
+}
+
+// SimilarProducts recommends alternatives to a product: active products of the
+// same category, ranked by how closely their extracted attributes and names
+// match. brandScope restricts candidates to the product's own brand or to
+// other brands.
+func (s *ProductService) SimilarProducts(ctx context.Context, id int, brandScope string, limit int) (*dto.SimilarProductsResponse, error) {
+	if brandScope == "" {
+		brandScope = BrandScopeAny
+	}
+	if brandScope != BrandScopeAny && brandScope != BrandScopeSame && brandScope != BrandScopeOther {
+		return nil, ErrInvalidBrandScope
+	}
+	if limit <= 0 {
+		limit = DefaultSimilarLimit
+	}
+	if limit > MaxSimilarLimit {
+		limit = MaxSimilarLimit
+	}
+
+	product, err := s.GetProduct(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := []string{
+		"is_active = 1",
+		"id != " + formatNumber(float64(product.ID)),
+		"category_name = " + quoteFilterValue(product.CategoryName),
+	}
+	switch brandScope {
+	case BrandScopeSame, BrandScopeOther:
+		if product.Brand == "" {
+			return nil, ErrNoBrand
+		}
+		operator := " = "
+		if brandScope == BrandScopeOther {
+			operator = " != "
+		}
+		filter = append(filter, FieldBrand+operator+quoteFilterValue(product.Brand))
+	}
+
+	// Names share little beyond the item type once brand and sizes are gone,
+	// so the query drops its most frequent words first when nothing matches
+	query := similarQuery(*product)
+	result, err := s.index.SearchWithContext(ctx, query, &meilisearch.SearchRequest{
+		Limit:            similarCandidates,
+		Filter:           filter,
+		MatchingStrategy: meilisearch.Frequency,
+		ShowRankingScore: true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	candidates, err := decodeHits(result.Hits)
+	if err != nil {
+		return nil, err
+	}
+
+	source, err := variantAttributes(*product)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &dto.SimilarProductsResponse{
+		ProductID:  product.ID,
+		Brand:      product.Brand,
+		BrandScope: brandScope,
+		Results:    []dto.SimilarProduct{},
+	}
+	for _, candidate := range candidates {
+		attributes, err := variantAttributes(candidate.Product)
+		if err != nil {
+			return nil, err
+		}
+		attributeScore, reasons := compareAttributes(source, attributes)
+
+		textScore := candidate.RankingScore
+		if query == "" {
+			textScore = 0
+		}
+		score := textScore
+		if attributeScore >= 0 {
+			score = similarTextWeight*textScore + similarAttributeWeight*attributeScore
+		}
+
+		if textScore >= 0.8 {
+			reasons = append(reasons, "similar name")
+		}
+		if candidate.Brand != "" && candidate.Brand != product.Brand {
+			reasons = append(reasons, "alternative brand "+candidate.Brand)
+		}
+		if len(reasons) == 0 {
+			reasons = append(reasons, "same category")
+		}
+
+		response.Results = append(response.Results, dto.SimilarProduct{
+			Product: candidate.Product,
+			Score:   math.Round(score*1000) / 1000,
+			Reason:  strings.Join(reasons, "; "),
+		})
+	}
+
+	sort.SliceStable(response.Results, func(i, j int) bool {
+		return response.Results[i].Score > response.Results[j].Score
+	})
+	if len(response.Results) > limit {
+		response.Results = response.Results[:limit]
+	}
+	return response, nil
+}
+
+// similarQuery builds the search text for finding alternatives: the product
+// name without its brand, sizes and model codes
+func similarQuery(product dto.Product) string {
+	words := strings.Fields(stripVariantDetails(product.Name))
+	brand := strings.Fields(product.Brand)
+	if len(brand) == 0 {
+		return strings.Join(words, " ")
+	}
+
+	query := make([]string, 0, len(words))
+	for i := 0; i < len(words); i++ {
+		if i+len(brand) <= len(words) && equalFoldWords(words[i:i+len(brand)], brand) {
+			i += len(brand) - 1
+			continue
+		}
+		query = append(query, words[i])
+	}
+	return strings.Join(query, " ")
+}
+
+// equalFoldWords reports whether two word lists match, ignoring case
+func equalFoldWords(a, b []string) bool {
+	for i := range a {
+		if !strings.EqualFold(a[i], b[i]) {
+			return false
+		}
+	}
+	return len(a) == len(b)
+}
+
+// compareAttributes scores how closely a candidate's attributes match the
+// source product's, from 0 to 1, and explains the matches. The score is -1
+// when the source product has none of the compared attributes.
+func compareAttributes(source, candidate map[string]interface{}) (float64, []string) {
+	var (
+		total   float64
+		count   int
+		reasons []string
+	)
+	for _, attribute := range similarAttributes {
+		want, ok := source[attribute.field]
+		if !ok {
+			continue
+		}
+		count++
+
+		got, ok := candidate[attribute.field]
+		if !ok {
+			continue
+		}
+
+		x, xNumeric := want.(float64)
+		y, yNumeric := got.(float64)
+		if xNumeric && yNumeric && attribute.unit != "" {
+			closeness := 1.0
+			if x != y {
+				closeness = 1 - math.Abs(x-y)/math.Max(math.Abs(x), math.Abs(y))
+			}
+			total += closeness
+
+			switch {
+			case x == y:
+				reasons = append(reasons, "same "+attribute.label+" ("+formatNumber(y)+" "+attribute.unit+")")
+			case closeness >= 1-similarTolerance:
+				reasons = append(reasons, "similar "+attribute.label+" ("+formatNumber(y)+" "+attribute.unit+
+					" vs "+formatNumber(x)+" "+attribute.unit+")")
+			}
+			continue
+		}
+
+		if attributeText(want) == attributeText(got) {
+			total++
+			reasons = append(reasons, "same "+attribute.label+" ("+attributeText(got)+")")
+		}
+	}
+
+	if count == 0 {
+		return -1, reasons
+	}
+	return total / float64(count), reasons
+}
+
+// attributeText formats an attribute value for comparison and display
+func attributeText(value interface{}) string {
+	switch v := value.(type) {
+	case float64:
+		return formatNumber(v)
+	case string:
+		return strings.ReplaceAll(v, "_", " ")
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,77 @@
+package service
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/meilisearch/meilisearch-go"
+)
+
+// FieldSoftDeleted is set to 1 on products soft-deleted through the API, so
+// re-indexing the catalog export does not bring them back
+const FieldSoftDeleted = "soft_deleted"
+
+// softDeletedPageSize is the number of soft-deleted IDs fetched per request
+const softDeletedPageSize = 1000
+
+// SoftDeletedIDs returns the IDs of the products soft-deleted through the
+// API. A missing index has none.
+func SoftDeletedIDs(ctx context.Context, index meilisearch.IndexManager) (map[string]bool, error) {
+	ids := make(map[string]bool)
+	for offset := int64(0); ; offset += softDeletedPageSize {
+		var result meilisearch.DocumentsResult
+		err := index.GetDocumentsWithContext(ctx, &meilisearch.DocumentsQuery{
+			Offset: offset,
+			Limit:  softDeletedPageSize,
+			Fields: []string{"id"},
+			Filter: FieldSoftDeleted + " = 1",
+		}, &result)
+		if isNotFound(err) {
+			return ids, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		for _, doc := range result.Results {
+			if id := documentID(doc["id"]); id != "" {
+				ids[id] = true
+			}
+		}
+		if len(result.Results) < softDeletedPageSize {
+			return ids, nil
+		}
+	}
+}
+
+// KeepSoftDeletes marks the catalog documents of soft-deleted products
+// inactive again before they replace the indexed ones, and returns how many
+// were found
+func KeepSoftDeletes(docs []map[string]interface{}, deleted map[string]bool) int {
+	kept := 0
+	for _, doc := range docs {
+		if !deleted[documentID(doc["id"])] {
+			continue
+		}
+		doc["is_active"] = 0
+		doc["status"] = StatusInactive
+		doc[FieldSoftDeleted] = 1
+		kept++
+	}
+	return kept
+}
+
+// documentID returns a document ID as a string, whether it was decoded as a
+// number or a string
+func documentID(value interface{}) string {
+	switch id := value.(type) {
+	case string:
+		return id
+	case float64:
+		return strconv.FormatFloat(id, 'f', -1, 64)
+	case int:
+		return strconv.Itoa(id)
+	default:
+		return ""
+	}
+}
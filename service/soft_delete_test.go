@@ -0,0 +1,129 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/meilisearch/meilisearch-go"
+)
+
+func TestKeepSoftDeletes(t *testing.T) {
+	docs := []map[string]interface{}{
+		{"id": float64(1), "is_active": "1", "status": StatusActive},
+		{"id": float64(2), "is_active": "1", "status": StatusActive},
+		{"id": "3", "is_active": "1", "status": StatusActive},
+		{"id": float64(4), "is_active": "0", "status": StatusInactive},
+	}
+	deleted := map[string]bool{"2": true, "3": true, "99": true}
+
+	if kept := KeepSoftDeletes(docs, deleted); kept != 2 {
+		t.Errorf("KeepSoftDeletes() = %d, want 2", kept)
+	}
+
+	want := []map[string]interface{}{
+		{"id": float64(1), "is_active": "1", "status": StatusActive},
+		{"id": float64(2), "is_active": 0, "status": StatusInactive, FieldSoftDeleted: 1},
+		{"id": "3", "is_active": 0, "status": StatusInactive, FieldSoftDeleted: 1},
+		{"id": float64(4), "is_active": "0", "status": StatusInactive},
+	}
+	if !reflect.DeepEqual(docs, want) {
+		t.Errorf("documents = %v, want %v", docs, want)
+	}
+}
+
+func TestDocumentID(t *testing.T) {
+	tests := []struct {
+		value interface{}
+		want  string
+	}{
+		{float64(1234), "1234"},
+		{"1234", "1234"},
+		{1234, "1234"},
+		{nil, ""},
+		{true, ""},
+	}
+
+	for _, tt := range tests {
+		if got := documentID(tt.value); got != tt.want {
+			t.Errorf("documentID(%v) = %q, want %q", tt.value, got, tt.want)
+		}
+	}
+}
+
+// documentsIndex serves GetDocuments from a fixed list of documents
+type documentsIndex struct {
+	meilisearch.IndexManager
+	docs    []map[string]interface{}
+	err     error
+	queries []meilisearch.DocumentsQuery
+}
+
+func (i *documentsIndex) GetDocumentsWithContext(_ context.Context, query *meilisearch.DocumentsQuery, result *meilisearch.DocumentsResult) error {
+	i.queries = append(i.queries, *query)
+	if i.err != nil {
+		return i.err
+	}
+	end := min(int(query.Offset+query.Limit), len(i.docs))
+	result.Results = i.docs[min(int(query.Offset), end):end]
+	return nil
+}
+
+func TestSoftDeletedIDs(t *testing.T) {
+	var pages []map[string]interface{}
+	for id := range softDeletedPageSize + 2 {
+		pages = append(pages, map[string]interface{}{"id": float64(id)})
+	}
+
+	tests := []struct {
+		name    string
+		index   *documentsIndex
+		count   int
+		queries int
+		wantErr bool
+	}{
+		{
+			name:    "none",
+			index:   &documentsIndex{},
+			queries: 1,
+		},
+		{
+			name:    "several pages",
+			index:   &documentsIndex{docs: pages},
+			count:   softDeletedPageSize + 2,
+			queries: 2,
+		},
+		{
+			name:    "missing index",
+			index:   &documentsIndex{err: &meilisearch.Error{StatusCode: http.StatusNotFound}},
+			queries: 1,
+		},
+		{
+			name:    "failing index",
+			index:   &documentsIndex{err: &meilisearch.Error{StatusCode: http.StatusInternalServerError}},
+			queries: 1,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ids, err := SoftDeletedIDs(context.Background(), tt.index)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("SoftDeletedIDs() error = %v, want error %v", err, tt.wantErr)
+			}
+			if len(ids) != tt.count {
+				t.Errorf("got %d IDs, want %d", len(ids), tt.count)
+			}
+			if len(tt.index.queries) != tt.queries {
+				t.Errorf("made %d requests, want %d", len(tt.index.queries), tt.queries)
+			}
+			for _, query := range tt.index.queries {
+				if query.Filter != FieldSoftDeleted+" = 1" {
+					t.Errorf("filter = %v, want %s = 1", query.Filter, FieldSoftDeleted)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,217 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"meilisearch/dto"
+
+	"github.com/meilisearch/meilisearch-go"
+)
+
+// SuggestConfig tunes search-as-you-type independently from full search
+type SuggestConfig struct {
+	// Limit is the default number of suggestions returned
+	Limit int
+	// MaxLimit caps the number of suggestions a client may request
+	MaxLimit int
+	// FetchFactor over-fetches hits so de-duplication still fills Limit
+	FetchFactor int
+	// MaxCategories caps the number of category suggestions
+	MaxCategories int
+	// SearchOn restricts matching to the fields shown in suggestions
+	SearchOn []string
+	// HighlightPreTag and HighlightPostTag wrap matching fragments
+	HighlightPreTag  string
+	HighlightPostTag string
+}
+
+// DefaultSuggestConfig returns the suggest tuning used by the API
+func DefaultSuggestConfig() SuggestConfig {
+	return SuggestConfig{
+		Limit:            8,
+		MaxLimit:         20,
+		FetchFactor:      4,
+		MaxCategories:    3,
+		SearchOn:         []string{"name", "sku", "category_name"},
+		HighlightPreTag:  "<em>",
+		HighlightPostTag: "</em>",
+	}
+}
+
+// mergeSuggestConfig overrides the fields of base that are set in cfg
+func mergeSuggestConfig(base, cfg SuggestConfig) SuggestConfig {
+	if cfg.Limit > 0 {
+		base.Limit = cfg.Limit
+	}
+	if cfg.MaxLimit > 0 {
+		base.MaxLimit = cfg.MaxLimit
+	}
+	if cfg.FetchFactor > 0 {
+		base.FetchFactor = cfg.FetchFactor
+	}
+	if cfg.MaxCategories > 0 {
+		base.MaxCategories = cfg.MaxCategories
+	}
+	if len(cfg.SearchOn) > 0 {
+		base.SearchOn = cfg.SearchOn
+	}
+	if cfg.HighlightPreTag != "" {
+		base.HighlightPreTag = cfg.HighlightPreTag
+	}
+	if cfg.HighlightPostTag != "" {
+		base.HighlightPostTag = cfg.HighlightPostTag
+	}
+	if base.Limit > base.MaxLimit {
+		base.Limit = base.MaxLimit
+	}
+	return base
+}
+
+var (
+	// loadRatingPattern matches load ratings such as "35 Kg" or "50 KG-WH"
+	loadRatingPattern = regexp.MustCompile(`(?i)\b\d+(\.\d+)?\s*kg\b`)
+	// whitespacePattern collapses runs of whitespace
+	whitespacePattern = regexp.MustCompile(`\s+`)
+)
+
+// suggestHit is the subset of document fields retrieved for suggestions
+type suggestHit struct {
+	ID           int    `json:"id"`
+	SKU          string `json:"sku"`
+	Name         string `json:"name"`
+	CategoryName string `json:"category_name"`
+	Formatted    struct {
+		SKU          string `json:"sku"`
+		Name         string `json:"name"`
+		CategoryName string `json:"category_name"`
+	} `json:"_formatted"`
+}
+
+// Suggest returns a short, de-duplicated list of products and categories for
+// a partially typed query. Meilisearch treats the last query word as a prefix,
+// so the query is only normalized here; a trailing space marks the last word
+// as complete and is preserved.
+func (s *ProductService) Suggest(ctx context.Context, query string, limit int) (*dto.SuggestResponse, error) {
+	cfg := s.suggest
+	if limit <= 0 {
+		limit = cfg.Limit
+	}
+	if limit > cfg.MaxLimit {
+		limit = cfg.MaxLimit
+	}
+
+	response := &dto.SuggestResponse{
+		Query:       query,
+		Suggestions: []dto.Suggestion{},
+		Categories:  []dto.CategorySuggestion{},
+	}
+
+	normalized := normalizeSuggestQuery(query)
+	if strings.TrimSpace(normalized) == "" {
+		return response, nil
+	}
+
+	result, err := s.index.SearchWithContext(ctx, normalized, &meilisearch.SearchRequest{
+		Limit:                 int64(limit * cfg.FetchFactor),
+		AttributesToSearchOn:  cfg.SearchOn,
+		AttributesToRetrieve:  []string{"id", "sku", "name", "category_name"},
+		AttributesToHighlight: cfg.SearchOn,
+		HighlightPreTag:       cfg.HighlightPreTag,
+		HighlightPostTag:      cfg.HighlightPostTag,
+		MatchingStrategy:      meilisearch.Last,
+		Filter:                []string{"is_active = 1"},
+		Facets:                []string{"category_name"},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var hits []suggestHit
+	data, err := json.Marshal(result.Hits)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &hits); err != nil {
+		return nil, fmt.Errorf("failed to decode suggest hits: %w", err)
+	}
+
+	response.Suggestions = dedupeSuggestions(hits, limit)
+	response.Categories = topCategories(result.FacetDistribution, cfg.MaxCategories)
+	response.ProcessingMs = result.ProcessingTimeMs
+	return response, nil
+}
+
+// dedupeSuggestions folds hits whose names differ only by load rating or
+// model code into a single suggestion, keeping the best-ranked hit
+func dedupeSuggestions(hits []suggestHit, limit int) []dto.Suggestion {
+	suggestions := []dto.Suggestion{}
+	seen := make(map[string]int)
+
+	for _, hit := range hits {
+		key := suggestionKey(hit.Name)
+		if idx, ok := seen[key]; ok {
+			suggestions[idx].Collapsed++
+			continue
+		}
+		if len(suggestions) >= limit {
+			continue
+		}
+
+		seen[key] = len(suggestions)
+		suggestions = append(suggestions, dto.Suggestion{
+			ID:                  hit.ID,
+			SKU:                 hit.SKU,
+			Name:                hit.Name,
+			CategoryName:        hit.CategoryName,
+			HighlightedName:     hit.Formatted.Name,
+			HighlightedSKU:      hit.Formatted.SKU,
+			HighlightedCategory: hit.Formatted.CategoryName,
+		})
+	}
+	return suggestions
+}
+
+// suggestionKey normalizes a product name for near-duplicate detection
+func suggestionKey(name string) string {
+	// Model codes follow a semicolon, e.g. "... 35 Kg Zinc ; KA 5332 - 9382865"
+	if idx := strings.Index(name, ";"); idx >= 0 {
+		name = name[:idx]
+	}
+	name = loadRatingPattern.ReplaceAllString(name, "")
+	name = whitespacePattern.ReplaceAllString(name, " ")
+	return strings.ToLower(strings.TrimSpace(name))
+}
+
+// normalizeSuggestQuery collapses whitespace while keeping a single trailing
+// space, which tells Meilisearch the last word is complete
+func normalizeSuggestQuery(query string) string {
+	trailing := strings.HasSuffix(query, " ")
+	query = strings.TrimSpace(whitespacePattern.ReplaceAllString(query, " "))
+	if trailing && query != "" {
+		query += " "
+	}
+	return query
+}
+
+// topCategories extracts the most frequent categories from a facet distribution
+func topCategories(distribution interface{}, max int) []dto.CategorySuggestion {
+	categories := []dto.CategorySuggestion{}
+	for name, count := range facetCounts(distribution, "category_name") {
+		categories = append(categories, dto.CategorySuggestion{Name: name, Count: count})
+	}
+	sort.Slice(categories, func(i, j int) bool {
+		if categories[i].Count != categories[j].Count {
+			return categories[i].Count > categories[j].Count
+		}
+		return categories[i].Name < categories[j].Name
+	})
+	if len(categories) > max {
+		categories = categories[:max]
+	}
+	return categories
+}
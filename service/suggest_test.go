@@ -0,0 +1,54 @@
+package service
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestWithSuggestConfig(t *testing.T) {
+	tests := []struct {
+		name   string
+		config SuggestConfig
+		want   func(SuggestConfig) SuggestConfig
+	}{
+		{
+			name:   "zero config keeps the defaults",
+			config: SuggestConfig{},
+			want:   func(c SuggestConfig) SuggestConfig { return c },
+		},
+		{
+			name:   "limits override the defaults",
+			config: SuggestConfig{Limit: 5, MaxLimit: 10, MaxCategories: 1},
+			want: func(c SuggestConfig) SuggestConfig {
+				c.Limit, c.MaxLimit, c.MaxCategories = 5, 10, 1
+				return c
+			},
+		},
+		{
+			name:   "limit is capped by the max limit",
+			config: SuggestConfig{Limit: 30},
+			want: func(c SuggestConfig) SuggestConfig {
+				c.Limit = c.MaxLimit
+				return c
+			},
+		},
+		{
+			name:   "highlight tags override the defaults",
+			config: SuggestConfig{HighlightPreTag: "<b>", HighlightPostTag: "</b>"},
+			want: func(c SuggestConfig) SuggestConfig {
+				c.HighlightPreTag, c.HighlightPostTag = "<b>", "</b>"
+				return c
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &ProductService{suggest: DefaultSuggestConfig()}
+			WithSuggestConfig(tt.config)(s)
+			if want := tt.want(DefaultSuggestConfig()); !reflect.DeepEqual(s.suggest, want) {
+				t.Errorf("suggest = %+v, want %+v", s.suggest, want)
+			}
+		})
+	}
+}
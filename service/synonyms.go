@@ -0,0 +1,252 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"meilisearch/dto"
+
+	"github.com/meilisearch/meilisearch-go"
+)
+
+// DefaultSynonymsFile is the synonym dictionary shipped with the repository
+const DefaultSynonymsFile = "config/synonyms.json"
+
+// Synonym dictionary errors
+var (
+	ErrSynonymGroupNotFound = errors.New("synonym group not found")
+	ErrSynonymGroupExists   = errors.New("synonym group already exists")
+	ErrInvalidSynonymGroup  = errors.New("synonym group needs at least two distinct terms")
+)
+
+// synonymIDPattern matches characters not allowed in generated group IDs
+var synonymIDPattern = regexp.MustCompile(`[^a-z0-9]+`)
+
+// LoadSynonyms reads a synonym dictionary from disk
+func LoadSynonyms(path string) (*dto.SynonymSet, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var set dto.SynonymSet
+	if err := json.Unmarshal(data, &set); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return &set, nil
+}
+
+// SaveSynonyms writes a synonym dictionary to disk, replacing the file atomically
+func SaveSynonyms(path string, set *dto.SynonymSet) error {
+	data, err := json.MarshalIndent(set, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".synonyms-*.json")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(append(data, '\n')); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// BuildSynonymMap expands synonym groups into the Meilisearch synonyms format
+func BuildSynonymMap(groups []dto.SynonymGroup) map[string][]string {
+	synonyms := make(map[string][]string)
+	for _, group := range groups {
+		terms := normalizeTerms(group.Terms)
+		if len(terms) < 2 {
+			continue
+		}
+
+		sources := terms
+		if group.OneWay {
+			sources = terms[:1]
+		}
+		for _, source := range sources {
+			for _, term := range terms {
+				if term != source {
+					synonyms[source] = appendUnique(synonyms[source], term)
+				}
+			}
+		}
+	}
+	return synonyms
+}
+
+// ApplySynonyms pushes a synonym dictionary to the index settings
+func ApplySynonyms(ctx context.Context, index meilisearch.IndexManager, set *dto.SynonymSet) (*meilisearch.TaskInfo, error) {
+	synonyms := BuildSynonymMap(set.Groups)
+	return index.UpdateSynonymsWithContext(ctx, &synonyms)
+}
+
+// SynonymService manages the synonym dictionary file and keeps the index
+// settings in sync with it
+type SynonymService struct {
+	mu    sync.Mutex
+	path  string
+	index meilisearch.IndexManager
+	set   *dto.SynonymSet
+}
+
+// NewSynonymService creates a synonym service backed by the given file. The
+// file is loaded on first use.
+func NewSynonymService(client meilisearch.ServiceManager, path string) *SynonymService {
+	return &SynonymService{
+		path:  path,
+		index: client.Index(ProductIndexUID),
+	}
+}
+
+// List returns the current synonym dictionary
+func (s *SynonymService) List() (*dto.SynonymSet, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.load(); err != nil {
+		return nil, err
+	}
+	set := *s.set
+	set.Groups = append([]dto.SynonymGroup(nil), s.set.Groups...)
+	return &set, nil
+}
+
+// Add adds a synonym group, saves the dictionary and pushes it to the index
+func (s *SynonymService) Add(ctx context.Context, group dto.SynonymGroup) (*dto.SynonymUpdateResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.load(); err != nil {
+		return nil, err
+	}
+
+	group.Terms = normalizeTerms(group.Terms)
+	if len(group.Terms) < 2 {
+		return nil, ErrInvalidSynonymGroup
+	}
+	group.ID = strings.TrimSpace(group.ID)
+	if group.ID == "" {
+		group.ID = strings.Trim(synonymIDPattern.ReplaceAllString(group.Terms[0], "-"), "-")
+	}
+	if s.find(group.ID) >= 0 {
+		return nil, ErrSynonymGroupExists
+	}
+
+	groups := append(append([]dto.SynonymGroup(nil), s.set.Groups...), group)
+	return s.update(ctx, groups)
+}
+
+// Remove deletes a synonym group, saves the dictionary and pushes it to the index
+func (s *SynonymService) Remove(ctx context.Context, id string) (*dto.SynonymUpdateResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.load(); err != nil {
+		return nil, err
+	}
+
+	idx := s.find(id)
+	if idx < 0 {
+		return nil, ErrSynonymGroupNotFound
+	}
+
+	groups := append([]dto.SynonymGroup(nil), s.set.Groups[:idx]...)
+	groups = append(groups, s.set.Groups[idx+1:]...)
+	return s.update(ctx, groups)
+}
+
+// update bumps the dictionary version, saves it and pushes it to the index.
+// The file is written first so the index never holds synonyms that were not
+// saved; if the index update fails the previous file is restored. Callers
+// must hold s.mu.
+func (s *SynonymService) update(ctx context.Context, groups []dto.SynonymGroup) (*dto.SynonymUpdateResponse, error) {
+	next := &dto.SynonymSet{
+		Version:   s.set.Version + 1,
+		UpdatedAt: time.Now().UTC(),
+		Groups:    groups,
+	}
+
+	if err := SaveSynonyms(s.path, next); err != nil {
+		return nil, fmt.Errorf("failed to save synonyms: %w", err)
+	}
+	task, err := ApplySynonyms(ctx, s.index, next)
+	if err != nil {
+		if rollbackErr := SaveSynonyms(s.path, s.set); rollbackErr != nil {
+			return nil, fmt.Errorf("failed to update index synonyms: %w (restoring %s also failed: %v)", err, s.path, rollbackErr)
+		}
+		return nil, fmt.Errorf("failed to update index synonyms: %w", err)
+	}
+
+	s.set = next
+	return &dto.SynonymUpdateResponse{
+		Version: next.Version,
+		Task:    newTaskStatus(task),
+	}, nil
+}
+
+// load reads the dictionary file if it has not been loaded yet. A missing file
+// is treated as an empty dictionary. Callers must hold s.mu.
+func (s *SynonymService) load() error {
+	if s.set != nil {
+		return nil
+	}
+
+	set, err := LoadSynonyms(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		set, err = &dto.SynonymSet{Groups: []dto.SynonymGroup{}}, nil
+	}
+	if err != nil {
+		return err
+	}
+	s.set = set
+	return nil
+}
+
+// find returns the position of a group by ID, or -1. Callers must hold s.mu.
+func (s *SynonymService) find(id string) int {
+	for i, group := range s.set.Groups {
+		if group.ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// normalizeTerms lowercases, trims and de-duplicates synonym terms, keeping order
+func normalizeTerms(terms []string) []string {
+	var normalized []string
+	for _, term := range terms {
+		term = strings.ToLower(strings.TrimSpace(whitespacePattern.ReplaceAllString(term, " ")))
+		if term != "" {
+			normalized = appendUnique(normalized, term)
+		}
+	}
+	return normalized
+}
+
+// appendUnique appends value to values unless it is already present
+func appendUnique(values []string, value string) []string {
+	for _, existing := range values {
+		if existing == value {
+			return values
+		}
+	}
+	return append(values, value)
+}
@@ -0,0 +1,125 @@
+package service
+
+import (
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Measurement kinds and the canonical unit each is converted to
+const (
+	KindLength = "length" // millimetres
+	KindMass   = "mass"   // kilograms
+	KindVolume = "volume" // millilitres
+)
+
+// DefaultUnitTolerance is the relative difference allowed between a size
+// converted from another unit and a product's nominal size. Catalog sizes are
+// nominal (a 16 in channel is 400 mm, not 406.4 mm), so exact conversion would
+// rarely match.
+const DefaultUnitTolerance = 0.02
+
+// unitSpec describes how a unit alias converts to its canonical unit
+type unitSpec struct {
+	unit   string
+	kind   string
+	factor float64
+}
+
+// unitAliases maps the unit spellings found in names and queries to their spec
+var unitAliases = map[string]unitSpec{
+	"mm":          {"mm", KindLength, 1},
+	"millimeter":  {"mm", KindLength, 1},
+	"millimeters": {"mm", KindLength, 1},
+	"millimetre":  {"mm", KindLength, 1},
+	"millimetres": {"mm", KindLength, 1},
+	"cm":          {"cm", KindLength, 10},
+	"in":          {"in", KindLength, 25.4},
+	"inch":        {"in", KindLength, 25.4},
+	"inches":      {"in", KindLength, 25.4},
+	`"`:           {"in", KindLength, 25.4},
+	"ft":          {"ft", KindLength, 304.8},
+	"feet":        {"ft", KindLength, 304.8},
+	"kg":          {"kg", KindMass, 1},
+	"kgs":         {"kg", KindMass, 1},
+	"kilogram":    {"kg", KindMass, 1},
+	"kilograms":   {"kg", KindMass, 1},
+	"g":           {"g", KindMass, 0.001},
+	"gm":          {"g", KindMass, 0.001},
+	"gms":         {"g", KindMass, 0.001},
+	"gram":        {"g", KindMass, 0.001},
+	"grams":       {"g", KindMass, 0.001},
+	"ml":          {"ml", KindVolume, 1},
+	"l":           {"l", KindVolume, 1000},
+	"ltr":         {"l", KindVolume, 1000},
+	"ltrs":        {"l", KindVolume, 1000},
+	"litre":       {"l", KindVolume, 1000},
+	"litres":      {"l", KindVolume, 1000},
+	"liter":       {"l", KindVolume, 1000},
+	"liters":      {"l", KindVolume, 1000},
+}
+
+// measurement is a number with a unit, converted to its canonical unit
+type measurement struct {
+	raw       string
+	value     float64
+	unit      string
+	kind      string
+	canonical float64
+}
+
+// measurementPattern finds numbers followed by a unit in free text such as
+// product names ("18 in- 450 mm", "4 in (90mm)", "50 KG-WH", "200 ML")
+var measurementPattern = regexp.MustCompile(`(?i)(\d+(?:\.\d+)?)\s*(millimet(?:er|re)s?|mm\b|cm\b|inch(?:es)?\b|in\b|"|ft\b|feet\b|kilograms?\b|kgs?\b|grams?\b|gms?\b|g\b|ml\b|lit(?:er|re)s?\b|ltrs?\b|l\b)`)
+
+// sheetSizePattern finds board sheet sizes in feet such as "8x4" or "7 x 3"
+var sheetSizePattern = regexp.MustCompile(`(?i)\b(\d)\s*x\s*(\d)\b`)
+
+// newMeasurement converts a value and unit alias into a measurement
+func newMeasurement(raw string, value float64, alias string) (measurement, bool) {
+	spec, ok := unitAliases[strings.ToLower(alias)]
+	if !ok {
+		return measurement{}, false
+	}
+	return measurement{
+		raw:       raw,
+		value:     value,
+		unit:      spec.unit,
+		kind:      spec.kind,
+		canonical: roundCanonical(value * spec.factor),
+	}, true
+}
+
+// scanMeasurements returns every measurement found in text, in order
+func scanMeasurements(text string) []measurement {
+	var measurements []measurement
+	for _, match := range measurementPattern.FindAllStringSubmatch(text, -1) {
+		value, err := strconv.ParseFloat(match[1], 64)
+		if err != nil {
+			continue
+		}
+		if m, ok := newMeasurement(match[0], value, match[2]); ok {
+			measurements = append(measurements, m)
+		}
+	}
+	return measurements
+}
+
+// normalizeSheetSize orders sheet dimensions largest first, e.g. "4x8" -> "8x4"
+func normalizeSheetSize(a, b string) string {
+	if a < b {
+		a, b = b, a
+	}
+	return a + "x" + b
+}
+
+// roundCanonical rounds converted values to two decimals to avoid float noise
+func roundCanonical(value float64) float64 {
+	return math.Round(value*100) / 100
+}
+
+// formatNumber formats a canonical value for a filter expression
+func formatNumber(value float64) string {
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
@@ -0,0 +1,65 @@
+package service
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestScanMeasurements(t *testing.T) {
+	type found struct {
+		unit      string
+		canonical float64
+	}
+	tests := []struct {
+		name string
+		text string
+		want []found
+	}{
+		{
+			name: "inches and millimetres",
+			text: "TELESCOPIC CHANNEL 18 in- 450 mm",
+			want: []found{{"in", 457.2}, {"mm", 450}},
+		},
+		{
+			name: "glued and bracketed units",
+			text: `DRAWER 4 in (90mm)`,
+			want: []found{{"in", 101.6}, {"mm", 90}},
+		},
+		{
+			name: "inch mark",
+			text: `20" TANDEM`,
+			want: []found{{"in", 508}},
+		},
+		{
+			name: "mass in kilograms and grams",
+			text: "50 KG-WH PACK OF 500 GMS",
+			want: []found{{"kg", 50}, {"g", 0.5}},
+		},
+		{
+			name: "volume in litres and millilitres",
+			text: "FEVICOL 1 LTR 200 ML",
+			want: []found{{"l", 1000}, {"ml", 200}},
+		},
+		{
+			name: "centimetres",
+			text: "PANEL 60 cm",
+			want: []found{{"cm", 600}},
+		},
+		{
+			name: "no units",
+			text: "HINGE 0 CRANK",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []found
+			for _, m := range scanMeasurements(tt.text) {
+				got = append(got, found{m.unit, m.canonical})
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("scanMeasurements(%q) = %v, want %v", tt.text, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,219 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"meilisearch/dto"
+
+	"github.com/meilisearch/meilisearch-go"
+)
+
+// FieldVariantGroup is the variant group key added to product documents at
+// index time
+const FieldVariantGroup = "variant_group"
+
+var (
+	// dimensionsPattern finds bare dimension lists such as "370 x 500 x 120"
+	// or the screw and nail sizes "6x19" and `14x1.5"`
+	dimensionsPattern = regexp.MustCompile(`(?i)\b\d+(?:\.\d+)?"?(?:\s*x\s*\d+(?:\.\d+)?"?)+`)
+	// packCountPattern finds piece counts such as "250 pc" or "1000pc"
+	packCountPattern = regexp.MustCompile(`(?i)\b\d+\s*pcs?\b`)
+	// trailingCodePattern finds an article number at the end of a name, as in
+	// "(WITH beech runners)- 9321105"
+	trailingCodePattern = regexp.MustCompile(`[-–]\s*[\d.]+\s*$`)
+	// variantKeySeparator collapses everything but letters and digits
+	variantKeySeparator = regexp.MustCompile(`[^a-z0-9]+`)
+)
+
+// VariantGroupKey derives the key shared by products that differ only in
+// size, load, pack size or crank, e.g. "FEVICOL SH 1 KG" and "FEVICOL SH 5 KG".
+// Model codes after a semicolon and trailing article numbers are ignored, as
+// they change with every variant.
+func VariantGroupKey(name, category string) string {
+	name = stripVariantDetails(name)
+	return strings.Trim(variantKeySeparator.ReplaceAllString(strings.ToLower(category+" "+name), "-"), "-")
+}
+
+// stripVariantDetails removes sizes, loads, pack sizes, cranks and model codes
+// from a product name, leaving the words that describe the item itself
+func stripVariantDetails(name string) string {
+	if idx := strings.Index(name, ";"); idx >= 0 {
+		name = name[:idx]
+	}
+	// Descriptions sometimes continue the name on further lines
+	if idx := strings.Index(name, "\n"); idx >= 0 {
+		name = name[:idx]
+	}
+	name = dimensionsPattern.ReplaceAllString(name, " ")
+	name = measurementPattern.ReplaceAllString(name, " ")
+	name = packCountPattern.ReplaceAllString(name, " ")
+	name = crankPattern.ReplaceAllString(name, " ")
+	name = trailingCodePattern.ReplaceAllString(strings.TrimSpace(name), "")
+	return strings.TrimSpace(whitespacePattern.ReplaceAllString(name, " "))
+}
+
+// MaxVariants caps the number of siblings listed for one product
+const MaxVariants = 100
+
+// Variants lists the products sharing a product's variant group, with the
+// attribute fields whose values differ between them. Inactive siblings are
+// left out, but the requested product is always listed.
+func (s *ProductService) Variants(ctx context.Context, id int) (*dto.VariantsResponse, error) {
+	product, err := s.GetProduct(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &dto.VariantsResponse{
+		ProductID:           product.ID,
+		VariantGroup:        product.VariantGroup,
+		DifferingAttributes: []string{},
+	}
+
+	products := []dto.Product{*product}
+	if product.VariantGroup != "" {
+		result, err := s.index.SearchWithContext(ctx, "", &meilisearch.SearchRequest{
+			Limit:  MaxVariants,
+			Filter: []string{"is_active = 1", FieldVariantGroup + " = " + quoteFilterValue(product.VariantGroup)},
+		})
+		if err != nil {
+			return nil, err
+		}
+		hits, err := decodeHits(result.Hits)
+		if err != nil {
+			return nil, err
+		}
+		for _, hit := range hits {
+			if hit.ID != product.ID {
+				products = append(products, hit.Product)
+			}
+		}
+	}
+
+	attributes := make([]map[string]interface{}, len(products))
+	for i, p := range products {
+		if attributes[i], err = variantAttributes(p); err != nil {
+			return nil, err
+		}
+	}
+	response.DifferingAttributes = differingAttributes(attributes)
+
+	response.Variants = make([]dto.ProductVariant, len(products))
+	for i, p := range products {
+		differing := make(map[string]interface{}, len(response.DifferingAttributes))
+		for _, field := range response.DifferingAttributes {
+			differing[field] = attributes[i][field]
+		}
+		response.Variants[i] = dto.ProductVariant{
+			ID:           p.ID,
+			SKU:          p.SKU,
+			Name:         p.Name,
+			SellingPrice: p.SellingPrice,
+			IsActive:     p.IsActive,
+			Current:      p.ID == product.ID,
+			Attributes:   differing,
+		}
+	}
+	sortVariants(response.Variants, response.DifferingAttributes)
+	return response, nil
+}
+
+// addVariantCounts sets the number of products in the variant group of each
+// hit, using one faceted search over the groups on the page. Inactive products
+// are counted only when the search included them.
+func (s *ProductService) addVariantCounts(ctx context.Context, hits []dto.ProductHit, includeInactive bool) error {
+	var groups []string
+	for _, hit := range hits {
+		if hit.VariantGroup != "" {
+			groups = append(groups, quoteFilterValue(hit.VariantGroup))
+		}
+	}
+	if len(groups) == 0 {
+		return nil
+	}
+
+	filter := []string{FieldVariantGroup + " IN [" + strings.Join(groups, ", ") + "]"}
+	if !includeInactive {
+		filter = append(filter, "is_active = 1")
+	}
+	result, err := s.index.SearchWithContext(ctx, "", &meilisearch.SearchRequest{
+		Limit:                1,
+		AttributesToRetrieve: []string{"id"},
+		Filter:               filter,
+		Facets:               []string{FieldVariantGroup},
+	})
+	if err != nil {
+		return err
+	}
+
+	counts := facetCounts(result.FacetDistribution, FieldVariantGroup)
+	for i := range hits {
+		hits[i].VariantCount = int(counts[hits[i].VariantGroup])
+	}
+	return nil
+}
+
+// variantAttributes returns the extracted attribute fields set on a product
+func variantAttributes(product dto.Product) (map[string]interface{}, error) {
+	data, err := json.Marshal(product)
+	if err != nil {
+		return nil, err
+	}
+	var fields map[string]interface{}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+
+	attributes := make(map[string]interface{})
+	for _, field := range AttributeFields {
+		if value, ok := fields[field]; ok {
+			attributes[field] = value
+		}
+	}
+	return attributes, nil
+}
+
+// differingAttributes lists the attribute fields whose values are not the same
+// for every variant, in AttributeFields order
+func differingAttributes(attributes []map[string]interface{}) []string {
+	differing := []string{}
+	for _, field := range AttributeFields {
+		values := make(map[string]bool)
+		for _, a := range attributes {
+			values[fmt.Sprint(a[field])] = true
+		}
+		if len(values) > 1 {
+			differing = append(differing, field)
+		}
+	}
+	return differing
+}
+
+// sortVariants orders variants by their differing attributes, smallest first,
+// then by ID
+func sortVariants(variants []dto.ProductVariant, fields []string) {
+	sort.SliceStable(variants, func(i, j int) bool {
+		for _, field := range fields {
+			a, b := variants[i].Attributes[field], variants[j].Attributes[field]
+			if fmt.Sprint(a) == fmt.Sprint(b) {
+				continue
+			}
+			// Variants missing an attribute sort last
+			if a == nil || b == nil {
+				return b == nil
+			}
+			if x, ok := a.(float64); ok {
+				if y, ok := b.(float64); ok {
+					return x < y
+				}
+			}
+			return fmt.Sprint(a) < fmt.Sprint(b)
+		}
+		return variants[i].ID < variants[j].ID
+	})
+}